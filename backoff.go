@@ -0,0 +1,233 @@
+package cronjob
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNoWork is returned by jobs which found nothing to do, backoff schedules wait longer
+// before running them again. it can be wrapped by the errors of the jobs.
+var ErrNoWork = errors.New("cronjob: no work")
+
+// BackoffConf represents a function to configure the behaviour of a backoff schedule.
+type BackoffConf func(*backoffSchedule)
+
+// WithBackoffJitter shortens each interval of a backoff schedule by a random part of up
+// to jitter (field) of it, between 0 and 1.
+func WithBackoffJitter(jitter float64) BackoffConf {
+	return func(s *backoffSchedule) {
+		s.jitter = math.Min(math.Max(jitter, 0), 1)
+	}
+}
+
+// ExponentialBackoff returns a schedule which runs every base (field), the interval
+// multiplied by factor (field) after each run of the job returning ErrNoWork, up to
+// max (field), and reset to base (field) after a run returning nil. runs returning other
+// errors keep the interval.
+//
+// example:
+//
+//	poll := func() error {
+//		if queue.Empty() {
+//			return cronjob.ErrNoWork
+//		}
+//		return process(queue)
+//	}
+//
+//	(*CronJob).AddFunc(poll, cronjob.ExponentialBackoff(time.Second, time.Minute, 2))
+//
+// poll runs every second while the queue has work, waiting 2, 4, 8, ... seconds up to a
+// minute while its empty.
+//
+// the intervals are counted from the end of the runs. the cronjob keeps the outcomes of
+// each job sharing the schedule apart.
+func ExponentialBackoff(base, max time.Duration, factor float64, confs ...BackoffConf) Schedule {
+	return newBackoff(base, max, math.Max(factor, 1), confs...)
+}
+
+// FibonacciBackoff returns a schedule which runs every base (field), the interval growing
+// with the fibonacci sequence (base, base, 2 * base, 3 * base, 5 * base, ...) after each
+// run of the job returning ErrNoWork, up to max (field), and reset to base (field) after
+// a run returning nil.
+//
+// see ExponentialBackoff.
+func FibonacciBackoff(base, max time.Duration, confs ...BackoffConf) Schedule {
+	return newBackoff(base, max, 0, confs...)
+}
+
+// newBackoff returns a backoff schedule growing by factor (field), with the fibonacci
+// sequence if 0.
+func newBackoff(base, max time.Duration, factor float64, confs ...BackoffConf) *backoffSchedule {
+	if base < highPrecision {
+		base = highPrecision
+	}
+	if max < base {
+		max = base
+	}
+
+	sched := &backoffSchedule{
+		base:   base,
+		max:    max,
+		factor: factor,
+		seed:   rand.New(rand.NewSource(time.Now().UnixNano())).Uint64(),
+	}
+
+	for _, conf := range confs {
+		conf(sched)
+	}
+	return sched
+}
+
+// BackoffSchedule ------------------------------------------------------------------
+
+type backoffSchedule struct {
+	// base and max are the shortest and longest intervals.
+	base, max time.Duration
+
+	// factor multiplies the interval after each idle run, the interval grows with the
+	// fibonacci sequence if 0.
+	factor float64
+
+	// jitter is the part of the intervals shortened by a random offset, derived from
+	// seed and the time the interval starts at.
+	jitter float64
+	seed   uint64
+
+	mu sync.Mutex
+
+	// idle is the number of runs which returned ErrNoWork since the last run returning nil.
+	idle int
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *backoffSchedule) Calculate(now time.Time) time.Duration {
+	return s.nextActivation.Sub(now)
+}
+
+func (s *backoffSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+// Observe grows the interval if err (field) is ErrNoWork and resets it if err (field) is
+// nil, the job failing doesnt tell whether it has work so other errors keep it.
+func (s *backoffSchedule) Observe(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case err == nil:
+		s.idle = 0
+
+	// stop counting once the interval reached the maximum.
+	case errors.Is(err, ErrNoWork) && s.interval(s.idle) < s.max:
+		s.idle++
+	}
+}
+
+// forJob returns a copy of the schedule without observed outcomes.
+func (s *backoffSchedule) forJob() Schedule {
+	return s.fresh()
+}
+
+// fresh returns a copy of the schedule without observed outcomes.
+func (s *backoffSchedule) fresh() *backoffSchedule {
+	return &backoffSchedule{
+		base:   s.base,
+		max:    s.max,
+		factor: s.factor,
+		jitter: s.jitter,
+		seed:   s.seed,
+	}
+}
+
+func (s *backoffSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	if s.base >= precision && s.max >= precision {
+		return s, false
+	}
+
+	raised := s.fresh()
+	if raised.base < precision {
+		raised.base = precision
+	}
+	if raised.max < precision {
+		raised.max = precision
+	}
+	return raised, true
+}
+
+// String returns the @backoff-exponential or @backoff-fibonacci descriptor of the
+// schedule, the observed outcomes arent part of it.
+func (s *backoffSchedule) String() string {
+	var str string
+	if s.factor > 0 {
+		str = fmt.Sprintf("@backoff-exponential %v %v %v", s.base, s.max, strconv.FormatFloat(s.factor, 'f', -1, 64))
+	} else {
+		str = fmt.Sprintf("@backoff-fibonacci %v %v", s.base, s.max)
+	}
+
+	if s.jitter > 0 {
+		str += " " + strconv.FormatFloat(s.jitter, 'f', -1, 64)
+	}
+	return str
+}
+
+func (s *backoffSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *backoffSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *backoffSchedule) UnmarshalText(text []byte) error {
+	parsed, err := textParser.Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	sched, ok := parsed.(*backoffSchedule)
+	if !ok {
+		return fmt.Errorf("cronjob: %q: cant unmarshal %T into %T", text, parsed, s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.base, s.max, s.factor, s.jitter, s.seed = sched.base, sched.max, sched.factor, sched.jitter, sched.seed
+	s.idle = 0
+	return nil
+}
+
+// Next returns the end of the current interval starting at: after (field).
+func (s *backoffSchedule) Next(after time.Time) time.Time {
+	s.mu.Lock()
+	interval := s.interval(s.idle)
+	s.mu.Unlock()
+
+	return after.Add(interval - randomDelay(s.seed, after, time.Duration(s.jitter*float64(interval))))
+}
+
+// interval returns the interval after idle (field) runs in a row which returned an
+// error.
+func (s *backoffSchedule) interval(idle int) time.Duration {
+	growth := 1.0
+	if s.factor > 0 {
+		growth = math.Pow(s.factor, float64(idle))
+	} else {
+		for a, i := 0.0, 0; i < idle; i++ {
+			a, growth = growth, a+growth
+		}
+	}
+
+	if d := float64(s.base) * growth; d < float64(s.max) {
+		return time.Duration(d)
+	}
+	return s.max
+}
@@ -0,0 +1,138 @@
+package cronjob
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	after := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	errFailed := errors.New("failed")
+
+	cases := []struct {
+		schedule Schedule
+		outcomes []error
+		expected []time.Duration
+	}{
+		{
+			ExponentialBackoff(time.Second, 10*time.Second, 2),
+			[]error{ErrNoWork, ErrNoWork, errFailed, ErrNoWork, ErrNoWork, nil, ErrNoWork},
+			[]time.Duration{2 * time.Second, 4 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, time.Second, 2 * time.Second},
+		},
+		{
+			FibonacciBackoff(time.Second, 10*time.Second),
+			[]error{ErrNoWork, ErrNoWork, ErrNoWork, ErrNoWork, ErrNoWork, nil},
+			[]time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second, 8 * time.Second, time.Second},
+		},
+
+		// wrapped ErrNoWork grows the interval, failures keep it.
+		{
+			ExponentialBackoff(time.Second, 10*time.Second, 2),
+			[]error{fmt.Errorf("poll: %w", ErrNoWork), errFailed, errFailed},
+			[]time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second},
+		},
+
+		// factors under 1 dont shrink the interval.
+		{
+			ExponentialBackoff(time.Second, 10*time.Second, 0.5),
+			[]error{ErrNoWork},
+			[]time.Duration{time.Second},
+		},
+	}
+
+	for i, c := range cases {
+		sched := c.schedule.(ObservingSchedule)
+		if got, want := nextAfter(sched, after).Sub(after), time.Second; got != want {
+			t.Fatalf("case %v: got: %v want: %v", i, got, want)
+		}
+
+		for j, err := range c.outcomes {
+			sched.Observe(err)
+
+			if got, want := nextAfter(sched, after).Sub(after), c.expected[j]; got != want {
+				t.Fatalf("case %v, outcome %v: got: %v want: %v", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	after := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	sched := ExponentialBackoff(time.Second, time.Minute, 2, WithBackoffJitter(0.5)).(ObservingSchedule)
+	sched.Observe(ErrNoWork)
+
+	for i := 0; i < 100; i++ {
+		got := nextAfter(sched, after).Sub(after)
+		if got <= time.Second || got > 2*time.Second {
+			t.Fatalf("activation %v: got: %v want: between %v and %v", i, got, time.Second, 2*time.Second)
+		}
+
+		after = after.Add(got)
+	}
+}
+
+func TestBackoffObservesJob(t *testing.T) {
+	t.Parallel()
+	var (
+		mu    sync.Mutex
+		count int
+		runs  []time.Time
+	)
+
+	c := New(WithHighPrecision())
+	c.AddFunc(func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		count++
+		runs = append(runs, time.Now())
+		if count > 4 {
+			return nil
+		}
+		return ErrNoWork
+	}, ExponentialBackoff(50*time.Millisecond, time.Second, 2))
+	c.Start()
+	defer c.Stop()
+
+	time.Sleep(time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// 50, 100, 200, 400 and 800 milliseconds after each empty run, then 50 once work is
+	// found.
+	if len(runs) < 4 {
+		t.Fatalf("got: %v runs want: at least 4", len(runs))
+	}
+	if first, last := runs[1].Sub(runs[0]), runs[3].Sub(runs[2]); last < 3*first {
+		t.Fatalf("got: %v then %v want: growing intervals", first, last)
+	}
+}
+
+func TestBackoffShared(t *testing.T) {
+	t.Parallel()
+	var idle, busy int32
+
+	// each job keeps its own interval.
+	sched := ExponentialBackoff(50*time.Millisecond, time.Second, 2)
+
+	c := New(WithHighPrecision())
+	c.AddFunc(func() error { atomic.AddInt32(&idle, 1); return ErrNoWork }, sched)
+	c.AddFunc(func() error { atomic.AddInt32(&busy, 1); return nil }, sched)
+	c.Start()
+	defer c.Stop()
+
+	time.Sleep(time.Second)
+
+	// 50, 150, 350 and 750 milliseconds in for the idle job, every 50 for the busy one.
+	if got, want := atomic.LoadInt32(&idle), int32(6); got > want {
+		t.Fatalf("got: %v runs want: at most %v", got, want)
+	}
+	if got, want := atomic.LoadInt32(&busy), int32(12); got < want {
+		t.Fatalf("got: %v runs want: at least %v", got, want)
+	}
+}
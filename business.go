@@ -0,0 +1,613 @@
+package cronjob
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar describes the business days: the days which arent on the weekend or
+// holidays.
+//
+// days are calendar dates, evaluated in the location of the times checked against the
+// calendar. a calendar shouldnt be modified once it is used by a schedule.
+type Calendar struct {
+	// weekend has the bit at position n set for the weekday n.
+	weekend uint8
+
+	// holidays are the dates of the holidays, at midnight UTC.
+	holidays map[time.Time]struct{}
+
+	// rules are the recurring holidays.
+	rules []holidayRule
+}
+
+// holidayRule is a recurring holiday, lasting days (field) from each occurrence of its
+// rule.
+type holidayRule struct {
+	rule *rruleSchedule
+	days int
+}
+
+// NewCalendar returns a calendar without holidays and weekend (field) as its weekend.
+//
+// example:
+//
+//	cronjob.NewCalendar(time.Saturday, time.Sunday)
+func NewCalendar(weekend ...time.Weekday) *Calendar {
+	c := &Calendar{
+		holidays: make(map[time.Time]struct{}),
+	}
+
+	for _, day := range weekend {
+		c.weekend |= 1 << uint(day)
+	}
+
+	return c
+}
+
+// AddHolidays adds the dates of dates (field) as holidays and returns the calendar.
+func (c *Calendar) AddHolidays(dates ...time.Time) *Calendar {
+	for _, date := range dates {
+		c.holidays[civilDate(date)] = struct{}{}
+	}
+
+	return c
+}
+
+// IsBusinessDay reports whether the date of t (field) is a business day.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	if c.weekend&(1<<uint(t.Weekday())) > 0 {
+		return false
+	}
+
+	date := civilDate(t)
+	if _, ok := c.holidays[date]; ok {
+		return false
+	}
+
+	for _, r := range c.rules {
+		// the holiday covers the date if an occurrence started in the last days of it.
+		occurrence := r.rule.Next(date.AddDate(0, 0, 1-r.days).Add(-time.Nanosecond))
+		if !occurrence.IsZero() && occurrence.Before(date.AddDate(0, 0, 1)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the weekend days, the holidays and the recurring holidays of the
+// calendar, in the format of the argument of the @business-days descriptor:
+//
+//	sat sun 2026-12-25 2026-12-26 (1 DTSTART:20260101T000000Z RRULE:FREQ=YEARLY)
+//
+// recurring holidays are the number of days they last followed by their rule.
+func (c *Calendar) String() string {
+	var args []string
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if c.weekend&(1<<uint(day)) > 0 {
+			args = append(args, strings.ToLower(day.String()[:3]))
+		}
+	}
+
+	var dates []string
+	for date := range c.holidays {
+		dates = append(dates, date.Format("2006-01-02"))
+	}
+	sort.Strings(dates)
+	args = append(args, dates...)
+
+	for _, r := range c.rules {
+		args = append(args, fmt.Sprintf("(%v %v)", r.days, r.rule.source))
+	}
+
+	return strings.Join(args, " ")
+}
+
+// parseCalendar parses the calendar written as args (field), see (*Calendar).String.
+func parseCalendar(args []string) (*Calendar, error) {
+	c := NewCalendar()
+
+	for _, arg := range args {
+		if day, ok := dowBounds.names[strings.ToLower(arg)]; ok {
+			c.weekend |= 1 << day
+			continue
+		}
+
+		if date, err := time.Parse("2006-01-02", arg); err == nil {
+			c.AddHolidays(date)
+			continue
+		}
+
+		daysAndRule := strings.SplitN(arg, " ", 2)
+		if len(daysAndRule) != 2 {
+			return nil, fmt.Errorf("expected a weekday, a date or a recurring holiday: %q", arg)
+		}
+
+		days, err := strconv.Atoi(daysAndRule[0])
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("days of recurring holiday should be a positive number: %q", arg)
+		}
+
+		sched, err := ParseRRule(daysAndRule[1])
+		if err != nil {
+			return nil, err
+		}
+
+		c.rules = append(c.rules, holidayRule{rule: sched.(*rruleSchedule), days: days})
+	}
+
+	return c, nil
+}
+
+// LoadCSV adds the holidays read from the CSV: r (field).
+//
+// the first column of each record is a date (2006-01-02), the other columns are ignored.
+// lines starting with # are comments and a header record is allowed.
+//
+// example:
+//
+//	date,name
+//	2026-12-25,Christmas Day
+//	2026-12-26,Boxing Day
+func (c *Calendar) LoadCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cronjob: failed to read holidays: %w", err)
+		}
+
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+		if err != nil {
+			// the header.
+			if line == 1 {
+				continue
+			}
+
+			return fmt.Errorf("cronjob: record %v: invalid date: %q", line, record[0])
+		}
+
+		c.AddHolidays(date)
+	}
+}
+
+// LoadICS adds the holidays read from the iCalendar (RFC 5545): r (field).
+//
+// each event (VEVENT) is a holiday on the dates from its DTSTART until its DTEND, end
+// excluded, or on the date of its DTSTART if it has no end. events recurring with an
+// RRULE are holidays on each of their occurrences.
+func (c *Calendar) LoadICS(r io.Reader) error {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return fmt.Errorf("cronjob: failed to read holidays: %w", err)
+	}
+
+	var (
+		inEvent               bool
+		dtstart, dtend, rrule string
+	)
+	for i, line := range lines {
+		name := strings.ToUpper(line)
+		if j := strings.IndexAny(name, ";:"); j != -1 {
+			name = name[:j]
+		}
+		value := line[strings.Index(line, ":")+1:]
+
+		switch {
+		case strings.EqualFold(line, "BEGIN:VEVENT"):
+			inEvent, dtstart, dtend, rrule = true, "", "", ""
+
+		case strings.EqualFold(line, "END:VEVENT"):
+			inEvent = false
+			if err := c.addEvent(dtstart, dtend, rrule); err != nil {
+				return fmt.Errorf("cronjob: line %v: %w", i+1, err)
+			}
+
+		case !inEvent:
+
+		case name == "DTSTART":
+			dtstart = value
+		case name == "DTEND":
+			dtend = value
+		case name == "RRULE":
+			rrule = value
+		}
+	}
+
+	return nil
+}
+
+// addEvent adds the holidays of an event from the values of its DTSTART, DTEND and
+// RRULE.
+func (c *Calendar) addEvent(dtstart, dtend, rrule string) error {
+	if dtstart == "" {
+		return fmt.Errorf("event without DTSTART")
+	}
+
+	start, _, err := parseRRuleTime(dtstart)
+	if err != nil {
+		return err
+	}
+
+	days := 1
+	if dtend != "" {
+		end, _, err := parseRRuleTime(dtend)
+		if err != nil {
+			return err
+		}
+
+		// the end is excluded, an end during a day covers the day.
+		if days = int(civilDate(end.Add(-time.Nanosecond)).Sub(civilDate(start))/day) + 1; days < 1 {
+			days = 1
+		}
+	}
+
+	if rrule != "" {
+		// the occurrences are dates, evaluated in UTC.
+		sched, err := ParseRRule(fmt.Sprintf("DTSTART:%vZ\nRRULE:%v", civilDate(start).Format("20060102T150405"), rrule))
+		if err != nil {
+			return err
+		}
+
+		c.rules = append(c.rules, holidayRule{rule: sched.(*rruleSchedule), days: days})
+		return nil
+	}
+
+	for d := 0; d < days; d++ {
+		c.AddHolidays(start.AddDate(0, 0, d))
+	}
+
+	return nil
+}
+
+// unfoldICS returns the lines of the iCalendar: r (field), joining the lines folded over
+// several lines.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if len(lines) > 0 {
+				lines[len(lines)-1] += line[1:]
+			}
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// civilDate returns the date of t (field) at midnight UTC.
+func civilDate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// startOfDay returns midnight of the day days (field) after the day of t (field), in the
+// location of t (field).
+func startOfDay(t time.Time, days int) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day+days, 0, 0, 0, 0, t.Location())
+}
+
+// OnBusinessDays returns a schedule which runs at the activations of schedule (field)
+// which are on the business days of calendar (field).
+//
+// example:
+//
+//	holidays := cronjob.NewCalendar(time.Saturday, time.Sunday)
+//	holidays.LoadCSV(file)
+//	cronjob.OnBusinessDays(sched, holidays)
+func OnBusinessDays(schedule Schedule, calendar *Calendar) Schedule {
+	return &businessDaysSchedule{
+		schedule: schedule,
+		calendar: calendar,
+	}
+}
+
+// RollForward returns a schedule which moves the activations of schedule (field) which
+// arent on business days of calendar (field) to the same time of the next business day.
+//
+// activations which end up at the same time run once.
+func RollForward(schedule Schedule, calendar *Calendar) Schedule {
+	return &rollSchedule{
+		schedule: schedule,
+		calendar: calendar,
+		forward:  true,
+	}
+}
+
+// RollBackward returns a schedule which moves the activations of schedule (field) which
+// arent on business days of calendar (field) to the same time of the previous business
+// day.
+//
+// activations which end up at the same time run once.
+func RollBackward(schedule Schedule, calendar *Calendar) Schedule {
+	return &rollSchedule{
+		schedule: schedule,
+		calendar: calendar,
+	}
+}
+
+// BusinessDaysSchedule ------------------------------------------------------------------
+
+type businessDaysSchedule struct {
+	schedule Schedule
+	calendar *Calendar
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *businessDaysSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *businessDaysSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *businessDaysSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	sched, ok := withDSTPolicy(s.schedule, policy)
+	if !ok {
+		return s, false
+	}
+
+	set := *s
+	set.schedule = sched
+	return &set, true
+}
+
+func (s *businessDaysSchedule) startTimes(start time.Time) (Schedule, bool) {
+	sched, ok := startTimes(s.schedule, start)
+	if !ok {
+		return s, false
+	}
+
+	started := *s
+	started.schedule = sched
+	return &started, true
+}
+
+func (s *businessDaysSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	sched, ok := withPrecision(s.schedule, precision)
+	if !ok {
+		return s, false
+	}
+
+	raised := *s
+	raised.schedule = sched
+	return &raised, true
+}
+
+// String returns the @business-days descriptor of the schedule.
+func (s *businessDaysSchedule) String() string {
+	return fmt.Sprintf("@business-days (%v) %v", s.calendar, groups(s.schedule))
+}
+
+func (s *businessDaysSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *businessDaysSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *businessDaysSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first activation after: after (field) which is on a business day.
+//
+// returns the zero time if none was found.
+func (s *businessDaysSchedule) Next(after time.Time) time.Time {
+	limit := after.Add(searchDays * day)
+
+	t := nextAfter(s.schedule, after)
+	for steps := 0; steps < combineSteps; steps++ {
+		if t.IsZero() || t.After(limit) {
+			return time.Time{}
+		}
+
+		if s.calendar.IsBusinessDay(t) {
+			return t
+		}
+
+		// skip the other activations of the day.
+		t = nextFrom(s.schedule, startOfDay(t, 1))
+	}
+
+	return time.Time{}
+}
+
+// RollSchedule ------------------------------------------------------------------
+
+type rollSchedule struct {
+	schedule Schedule
+	calendar *Calendar
+
+	// forward is set if the activations roll to the next business day, otherwise they
+	// roll to the previous one.
+	forward bool
+
+	// dst is the policy used for rolled times skipped by daylight saving time.
+	dst DSTPolicy
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *rollSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *rollSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *rollSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	sched, ok := withDSTPolicy(s.schedule, policy)
+	if !ok && s.dst == policy {
+		return s, false
+	}
+
+	set := *s
+	set.schedule, set.dst = sched, policy
+	return &set, true
+}
+
+func (s *rollSchedule) startTimes(start time.Time) (Schedule, bool) {
+	sched, ok := startTimes(s.schedule, start)
+	if !ok {
+		return s, false
+	}
+
+	started := *s
+	started.schedule = sched
+	return &started, true
+}
+
+func (s *rollSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	sched, ok := withPrecision(s.schedule, precision)
+	if !ok {
+		return s, false
+	}
+
+	raised := *s
+	raised.schedule = sched
+	return &raised, true
+}
+
+// String returns the @roll-forward or @roll-backward descriptor of the schedule.
+func (s *rollSchedule) String() string {
+	name := "@roll-backward"
+	if s.forward {
+		name = "@roll-forward"
+	}
+
+	return withDST(s.dst, fmt.Sprintf("%v (%v) %v", name, s.calendar, groups(s.schedule)))
+}
+
+func (s *rollSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *rollSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *rollSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first rolled activation after: after (field).
+//
+// returns the zero time if none was found.
+func (s *rollSchedule) Next(after time.Time) time.Time {
+	limit := after.Add(searchDays * day)
+
+	// activations on the days before the next business day can roll forward past
+	// after (field), start looking from the first of them.
+	from := after
+	if s.forward {
+		first := wallReading(after)
+		for i := 0; i < searchDays && !s.calendar.IsBusinessDay(first); i++ {
+			first = first.AddDate(0, 0, 1)
+		}
+		for i := 0; i < searchDays && !s.calendar.IsBusinessDay(first.AddDate(0, 0, -1)); i++ {
+			first = first.AddDate(0, 0, -1)
+		}
+
+		if start := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, after.Location()); start.Before(after) {
+			from = start.Add(-time.Nanosecond)
+		}
+	}
+
+	var next time.Time
+	t := nextAfter(s.schedule, from)
+	for steps := 0; steps < combineSteps; steps++ {
+		if t.IsZero() || t.After(limit) {
+			break
+		}
+
+		rolled, ok := s.roll(t)
+		if !ok {
+			t = nextAfter(s.schedule, t)
+			continue
+		}
+
+		// later activations roll to later days.
+		if !next.IsZero() && civilDate(rolled).After(civilDate(next)) {
+			break
+		}
+
+		if !rolled.After(after) {
+			// skip the activations of the day which roll before after (field).
+			t = nextAfter(s.schedule, t.Add(after.Sub(rolled)))
+			continue
+		}
+
+		if next.IsZero() || rolled.Before(next) {
+			next = rolled
+		}
+
+		// the other activations of the day roll to later times of the same day.
+		t = nextFrom(s.schedule, startOfDay(t, 1))
+	}
+
+	return next
+}
+
+// roll returns the time of the activation at t (field) rolled to a business day.
+//
+// reports false if no business day was found or the rolled wall clock reading was
+// skipped by daylight saving time and the policy is DSTSkip.
+func (s *rollSchedule) roll(t time.Time) (time.Time, bool) {
+	if s.calendar.IsBusinessDay(t) {
+		return t, true
+	}
+
+	step := -1
+	if s.forward {
+		step = 1
+	}
+
+	wall := wallReading(t)
+	for i := 0; i < searchDays; i++ {
+		if wall = wall.AddDate(0, 0, step); s.calendar.IsBusinessDay(wall) {
+			return wallClock(wall, t.Location(), s.dst)
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,193 @@
+package cronjob
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarLoadCSV(t *testing.T) {
+	cal := NewCalendar(time.Saturday, time.Sunday)
+	err := cal.LoadCSV(strings.NewReader("date,name\n# comment\n2026-12-25,Christmas Day\n2026-12-28, Boxing Day (substitute)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		date     string
+		expected bool
+	}{
+		{"2026-12-24T23:00:00Z", true},
+		{"2026-12-25T09:00:00Z", false},
+		{"2026-12-26T09:00:00Z", false},
+		{"2026-12-27T09:00:00Z", false},
+		{"2026-12-28T09:00:00Z", false},
+		{"2026-12-29T00:00:00Z", true},
+
+		// dates are evaluated in the location of the time.
+		{"2026-12-24T23:00:00-02:00", true},
+		{"2026-12-25T01:00:00+02:00", false},
+	}
+
+	for _, c := range cases {
+		if got := cal.IsBusinessDay(mustParseTime(t, c.date)); got != c.expected {
+			t.Fatalf("%v: got: %v want: %v", c.date, got, c.expected)
+		}
+	}
+
+	if err := NewCalendar().LoadCSV(strings.NewReader("2026-12-25\n25/12/2026\n")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestCalendarLoadICS(t *testing.T) {
+	const ics = "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20261224\r\n" +
+		"DTEND;VALUE=DATE:20261227\r\n" +
+		"SUMMARY:Christmas holidays, folded over\r\n" +
+		" two lines\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20200101\r\n" +
+		"RRULE:FREQ=YEARLY\r\n" +
+		"SUMMARY:New Year's Day\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal := NewCalendar()
+	if err := cal.LoadICS(strings.NewReader(ics)); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		date     string
+		expected bool
+	}{
+		{"2026-12-23T12:00:00Z", true},
+		{"2026-12-24T12:00:00Z", false},
+		{"2026-12-26T12:00:00Z", false},
+		{"2026-12-27T12:00:00Z", true},
+		{"2027-01-01T12:00:00Z", false},
+		{"2030-01-01T00:00:00Z", false},
+		{"2030-01-02T00:00:00Z", true},
+		{"2019-01-01T00:00:00Z", true},
+	}
+
+	for _, c := range cases {
+		if got := cal.IsBusinessDay(mustParseTime(t, c.date)); got != c.expected {
+			t.Fatalf("%v: got: %v want: %v", c.date, got, c.expected)
+		}
+	}
+
+	for _, ics := range []string{
+		"BEGIN:VEVENT\nSUMMARY:no start\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:2026-12-25\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20261225\nRRULE:FREQ=FORTNIGHTLY\nEND:VEVENT\n",
+	} {
+		if err := NewCalendar().LoadICS(strings.NewReader(ics)); err == nil {
+			t.Fatalf("%q: expected error", ics)
+		}
+	}
+}
+
+func TestBusinessDaySchedules(t *testing.T) {
+	cal := NewCalendar(time.Saturday, time.Sunday).AddHolidays(
+		time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC),
+	)
+
+	cases := []struct {
+		name     string
+		schedule func() Schedule
+		after    string
+		expected []string
+	}{
+		{
+			name: "on business days",
+			schedule: func() Schedule {
+				return OnBusinessDays(spec("0 9 * * *")(), cal)
+			},
+			after:    "2026-12-24T10:00:00Z",
+			expected: []string{"2026-12-29T09:00:00Z", "2026-12-30T09:00:00Z"},
+		},
+		{
+			name: "on business days every second",
+			schedule: func() Schedule {
+				sched, _ := NewParser(SecondField | MinuteField | HourField | DomField | MonthField | DowField).Parse("* * * * * *")
+				return OnBusinessDays(sched, cal)
+			},
+			after:    "2026-12-24T23:59:59Z",
+			expected: []string{"2026-12-29T00:00:00Z", "2026-12-29T00:00:01Z"},
+		},
+		{
+			name: "roll forward",
+			schedule: func() Schedule {
+				return RollForward(spec("0 9 1 * *")(), cal)
+			},
+			after:    "2026-10-17T10:00:00Z",
+			expected: []string{"2026-11-02T09:00:00Z", "2026-12-01T09:00:00Z"},
+		},
+		{
+			name: "roll forward activation before after",
+			schedule: func() Schedule {
+				return RollForward(spec("0 9 1 * *")(), cal)
+			},
+			after:    "2026-11-01T10:00:00Z",
+			expected: []string{"2026-11-02T09:00:00Z", "2026-12-01T09:00:00Z"},
+		},
+		{
+			name: "roll forward shared activations",
+			schedule: func() Schedule {
+				return RollForward(spec("0 9 * * *")(), cal)
+			},
+			after:    "2026-10-16T10:00:00Z",
+			expected: []string{"2026-10-19T09:00:00Z", "2026-10-20T09:00:00Z"},
+		},
+		{
+			name: "roll forward order",
+			schedule: func() Schedule {
+				return RollForward(Union(spec("0 23 * * 6")(), spec("0 8 * * 1")()), cal)
+			},
+			after:    "2026-10-16T10:00:00Z",
+			expected: []string{"2026-10-19T08:00:00Z", "2026-10-19T23:00:00Z", "2026-10-26T08:00:00Z"},
+		},
+		{
+			name: "roll backward",
+			schedule: func() Schedule {
+				return RollBackward(spec("0 9 1 * *")(), cal)
+			},
+			after:    "2026-10-29T10:00:00Z",
+			expected: []string{"2026-10-30T09:00:00Z", "2026-12-01T09:00:00Z"},
+		},
+		{
+			name: "roll backward holidays",
+			schedule: func() Schedule {
+				return RollBackward(spec("0 9 28 12 *")(), cal)
+			},
+			after:    "2026-12-01T00:00:00Z",
+			expected: []string{"2026-12-24T09:00:00Z", "2027-12-28T09:00:00Z"},
+		},
+		{
+			name: "roll backward shared activations",
+			schedule: func() Schedule {
+				return RollBackward(spec("0 9 * * *")(), cal)
+			},
+			after:    "2026-10-16T10:00:00Z",
+			expected: []string{"2026-10-19T09:00:00Z", "2026-10-20T09:00:00Z"},
+		},
+	}
+
+	for _, c := range cases {
+		sched := c.schedule()
+
+		after := mustParseTime(t, c.after)
+		for i, expected := range c.expected {
+			got := activate(sched, after)
+			if want := mustParseTime(t, expected); !got.Equal(want) {
+				t.Fatalf("%v: activation %v: got: %v want: %v", c.name, i, got, want)
+			}
+			after = got
+		}
+	}
+}
@@ -21,27 +21,40 @@ func MergeChains(n ...Chain) (merged Chain) {
 }
 
 // Run runs job (field) with the chains.
-func (c Chain) Run(job FuncJob) {
+//
+// returns the error of the decorated job.
+func (c Chain) Run(job FuncJob) error {
 	// decorate job.
 	for i := range c {
 		job = c[len(c)-i-1](job)
 	}
 
 	// run decorated job.
-	job()
+	return job()
 }
 
 // Retry will retry your job decorated with past chains max (field) times with a timeout (field)
 // delay.
 //
+// timeouts under a second are raised to it, use WithRetry to retry the jobs of a cronjob in
+// high precision mode (see WithHighPrecision) with timeouts down to a millisecond.
+//
 // Retry MUST only be added first in cronjob.NewChain() if you want all the chains to run properly.
 // Not adding retry as the first argument in NewChain will cause unexpected behaviour.
 func Retry(timeout time.Duration, max int) func(FuncJob) FuncJob {
+	return retry(timeout, max, defaultPrecision)
+}
+
+// retry is Retry with the timeout raised to precision (field) instead of a second.
+func retry(timeout time.Duration, max int, precision time.Duration) func(FuncJob) FuncJob {
 	if max <= 0 {
 		max = 1
 	}
-	if timeout < time.Second {
-		timeout = time.Second
+	if precision <= 0 {
+		precision = defaultPrecision
+	}
+	if timeout < precision {
+		timeout = precision
 	}
 
 	return func(fj FuncJob) FuncJob {
@@ -54,15 +67,15 @@ func Retry(timeout time.Duration, max int) func(FuncJob) FuncJob {
 			// use 1 to compensate for first error checking call.
 			for i := 1; i < max; i++ {
 				<-ticker.C
-				if err := fj(); err == nil {
+				if err = fj(); err == nil {
 					break
 				}
 			}
 		}
 
-		// ends chain.
+		// ends chain with the error of the last try.
 		return func() error {
-			return nil
+			return err
 		}
 	}
 }
@@ -84,7 +84,9 @@ func TestRetry(t *testing.T) {
 			}
 			return nil
 		}
-		NewChain(Retry(0, 4)).Run(job)
+		if err := NewChain(Retry(0, 4)).Run(job); err != nil {
+			t.Fatal(err)
+		}
 
 		if got, want := count, 2; got != want {
 			t.Fatalf("want: %v got: %v\n", want, got)
@@ -99,7 +101,9 @@ func TestRetry(t *testing.T) {
 			count++
 			return fmt.Errorf("error")
 		}
-		NewChain(Retry(0, 4)).Run(job)
+		if err := NewChain(Retry(0, 4)).Run(job); err == nil {
+			t.Fatal("expected error")
+		}
 
 		if got, want := count, 4; got != want {
 			t.Fatalf("want: %v got: %v\n", want, got)
@@ -133,6 +137,30 @@ func TestRetry(t *testing.T) {
 		}
 	})
 
+	t.Run("Test High Precision", func(t *testing.T) {
+		t.Parallel()
+		var count int
+
+		wantErr := fmt.Errorf("error")
+		job := func() error {
+			count++
+			return wantErr
+		}
+
+		// the timeout is raised to a millisecond instead of a second.
+		start := time.Now()
+		if err := NewChain(retry(0, 4, highPrecision)).Run(job); err != wantErr {
+			t.Fatalf("want: %v got: %v\n", wantErr, err)
+		}
+
+		if got, want := time.Since(start), time.Second; got >= want {
+			t.Fatalf("want: under %v got: %v\n", want, got)
+		}
+		if got, want := count, 4; got != want {
+			t.Fatalf("want: %v got: %v\n", want, got)
+		}
+	})
+
 }
 
 func TestMergeChains(t *testing.T) {
@@ -0,0 +1,651 @@
+package cronjob
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// combineSteps is the number of activations the intersect and except schedules go
+// through looking for their next activation before giving up.
+const combineSteps = 1 << 16
+
+// Union returns a schedule which runs at the activations of any of schedules (field).
+//
+// example:
+//
+//	cronjob.Union(weekdays, weekends)
+//
+// the schedule runs at the activations of both weekdays and weekends, activations at the
+// same time run once.
+func Union(schedules ...Schedule) Schedule {
+	return &unionSchedule{
+		schedules: schedules,
+	}
+}
+
+// Intersect returns a schedule which runs at the activations shared by all of
+// schedules (field).
+//
+// example:
+//
+//	quarterly, _ := cronjob.Parse("*/15 * * * *")
+//	officeHours, _ := cronjob.Parse("* 9-16 * * 1-5")
+//	cronjob.Intersect(quarterly, officeHours)
+//
+// the schedule runs every 15 minutes between 09:00 and 17:00 on weekdays.
+//
+// the schedules are expected to run at fixed times (Parse, EveryFixed, ...), Every has
+// no fixed times to be shared. the schedule gives up after 65536 activations which
+// arent shared or 8 years without a shared activation.
+func Intersect(schedules ...Schedule) Schedule {
+	return &intersectSchedule{
+		schedules: schedules,
+	}
+}
+
+// Except returns a schedule which runs at the activations of base (field) which arent
+// activations of exclusion (field).
+//
+// example:
+//
+//	christmas, _ := cronjob.Parse("* * 25 12 *")
+//	cronjob.Except(quarterly, christmas)
+//
+// the schedule runs every 15 minutes, except on the 25th of december (christmas runs
+// every minute of the day).
+//
+// the schedule gives up after 65536 excluded activations in a row or 8 years without
+// an activation.
+func Except(base, exclusion Schedule) Schedule {
+	return &exceptSchedule{
+		base:      base,
+		exclusion: exclusion,
+	}
+}
+
+// Between returns a schedule which runs at the activations of schedule (field) from
+// start (field) until end (field), end excluded.
+//
+// a zero start or end leaves the window open on that side.
+func Between(start, end time.Time, schedule Schedule) Schedule {
+	return &betweenSchedule{
+		start:    start,
+		end:      end,
+		schedule: schedule,
+	}
+}
+
+// Times returns a schedule which runs at the first n (field) activations of
+// schedule (field), counted from when the schedule is added to the cronjob.
+//
+// each job adding the schedule counts its own activations, Preview counts them from the
+// time it is given.
+func Times(n int, schedule Schedule) Schedule {
+	return TimesFrom(n, time.Time{}, schedule)
+}
+
+// TimesFrom returns a schedule which runs at the first n (field) activations of
+// schedule (field) after start (field), a zero start counts them like Times.
+func TimesFrom(n int, start time.Time, schedule Schedule) Schedule {
+	if n < 0 {
+		n = 0
+	}
+
+	return &timesSchedule{
+		n:        n,
+		start:    start,
+		schedule: schedule,
+	}
+}
+
+// startSchedule is implemented by the Times schedules and by the schedules wrapping them.
+type startSchedule interface {
+	// startTimes returns a copy of the schedule with the Times schedules which werent
+	// started counting from the time given, or the schedule itself and false if there
+	// were none.
+	startTimes(time.Time) (Schedule, bool)
+}
+
+// startTimes returns schedule (field) with the Times schedules it holds which werent
+// started counting their activations from start (field), reports whether there were any.
+//
+// schedule (field) is never changed, it can be shared by other jobs and cronjobs.
+func startTimes(schedule Schedule, start time.Time) (Schedule, bool) {
+	if sched, ok := schedule.(startSchedule); ok {
+		return sched.startTimes(start)
+	}
+
+	return schedule, false
+}
+
+// startAllTimes returns all of schedules (field) with the Times schedules they hold
+// started from start (field), reports whether any of them changed.
+func startAllTimes(start time.Time, schedules ...Schedule) ([]Schedule, bool) {
+	var (
+		started = make([]Schedule, len(schedules))
+		changed bool
+	)
+	for i, sched := range schedules {
+		var ok bool
+		started[i], ok = startTimes(sched, start)
+		changed = changed || ok
+	}
+
+	return started, changed
+}
+
+// withDSTPolicies returns all of schedules (field) with the policy: policy (field),
+// reports whether any of them changed.
+func withDSTPolicies(policy DSTPolicy, schedules ...Schedule) ([]Schedule, bool) {
+	var (
+		set     = make([]Schedule, len(schedules))
+		changed bool
+	)
+	for i, sched := range schedules {
+		var ok bool
+		set[i], ok = withDSTPolicy(sched, policy)
+		changed = changed || ok
+	}
+
+	return set, changed
+}
+
+// UnionSchedule ------------------------------------------------------------------
+
+type unionSchedule struct {
+	schedules []Schedule
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *unionSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *unionSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *unionSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	schedules, ok := withDSTPolicies(policy, s.schedules...)
+	if !ok {
+		return s, false
+	}
+
+	return &unionSchedule{schedules: schedules}, true
+}
+
+func (s *unionSchedule) startTimes(start time.Time) (Schedule, bool) {
+	schedules, ok := startAllTimes(start, s.schedules...)
+	if !ok {
+		return s, false
+	}
+
+	return &unionSchedule{schedules: schedules}, true
+}
+
+func (s *unionSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	schedules, ok := withPrecisions(precision, s.schedules...)
+	if !ok {
+		return s, false
+	}
+
+	return &unionSchedule{schedules: schedules}, true
+}
+
+// String returns the @union descriptor of the schedules.
+func (s *unionSchedule) String() string {
+	return "@union " + groups(s.schedules...)
+}
+
+func (s *unionSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *unionSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *unionSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first activation of any of the schedules after: after (field).
+//
+// returns the zero time if none of them has further activations.
+func (s *unionSchedule) Next(after time.Time) time.Time {
+	var next time.Time
+	for _, sched := range s.schedules {
+		if t := nextAfter(sched, after); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+
+	return next
+}
+
+// IntersectSchedule ------------------------------------------------------------------
+
+type intersectSchedule struct {
+	schedules []Schedule
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *intersectSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *intersectSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *intersectSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	schedules, ok := withDSTPolicies(policy, s.schedules...)
+	if !ok {
+		return s, false
+	}
+
+	return &intersectSchedule{schedules: schedules}, true
+}
+
+func (s *intersectSchedule) startTimes(start time.Time) (Schedule, bool) {
+	schedules, ok := startAllTimes(start, s.schedules...)
+	if !ok {
+		return s, false
+	}
+
+	return &intersectSchedule{schedules: schedules}, true
+}
+
+func (s *intersectSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	schedules, ok := withPrecisions(precision, s.schedules...)
+	if !ok {
+		return s, false
+	}
+
+	return &intersectSchedule{schedules: schedules}, true
+}
+
+// String returns the @intersect descriptor of the schedules.
+func (s *intersectSchedule) String() string {
+	return "@intersect " + groups(s.schedules...)
+}
+
+func (s *intersectSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *intersectSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *intersectSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first activation shared by all the schedules after: after (field).
+//
+// returns the zero time if none was found.
+func (s *intersectSchedule) Next(after time.Time) time.Time {
+	if len(s.schedules) == 0 {
+		return time.Time{}
+	}
+
+	target := nextAfter(s.schedules[0], after)
+	limit := after.Add(searchDays * day)
+
+	// move each schedule to the target, until they all agree on it.
+	for steps := 0; steps < combineSteps; steps++ {
+		if target.IsZero() || target.After(limit) {
+			return time.Time{}
+		}
+
+		agree := true
+		for _, sched := range s.schedules {
+			t := nextFrom(sched, target)
+			if t.IsZero() {
+				return time.Time{}
+			}
+
+			if t.After(target) {
+				target, agree = t, false
+				break
+			}
+		}
+
+		if agree {
+			return target
+		}
+	}
+
+	return time.Time{}
+}
+
+// ExceptSchedule ------------------------------------------------------------------
+
+type exceptSchedule struct {
+	base, exclusion Schedule
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *exceptSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *exceptSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *exceptSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	schedules, ok := withDSTPolicies(policy, s.base, s.exclusion)
+	if !ok {
+		return s, false
+	}
+
+	return &exceptSchedule{base: schedules[0], exclusion: schedules[1]}, true
+}
+
+func (s *exceptSchedule) startTimes(start time.Time) (Schedule, bool) {
+	schedules, ok := startAllTimes(start, s.base, s.exclusion)
+	if !ok {
+		return s, false
+	}
+
+	return &exceptSchedule{base: schedules[0], exclusion: schedules[1]}, true
+}
+
+func (s *exceptSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	schedules, ok := withPrecisions(precision, s.base, s.exclusion)
+	if !ok {
+		return s, false
+	}
+
+	return &exceptSchedule{base: schedules[0], exclusion: schedules[1]}, true
+}
+
+// String returns the @except descriptor of the schedules.
+func (s *exceptSchedule) String() string {
+	return "@except " + groups(s.base, s.exclusion)
+}
+
+func (s *exceptSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *exceptSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *exceptSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first activation of the base schedule after: after (field) which
+// isnt an activation of the exclusion schedule.
+//
+// returns the zero time if none was found.
+func (s *exceptSchedule) Next(after time.Time) time.Time {
+	limit := after.Add(searchDays * day)
+
+	t := nextAfter(s.base, after)
+	for steps := 0; steps < combineSteps; steps++ {
+		if t.IsZero() || t.After(limit) {
+			return time.Time{}
+		}
+
+		if !nextFrom(s.exclusion, t).Equal(t) {
+			return t
+		}
+
+		t = nextAfter(s.base, t)
+	}
+
+	return time.Time{}
+}
+
+// BetweenSchedule ------------------------------------------------------------------
+
+type betweenSchedule struct {
+	start, end time.Time
+
+	schedule Schedule
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *betweenSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *betweenSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *betweenSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	sched, ok := withDSTPolicy(s.schedule, policy)
+	if !ok {
+		return s, false
+	}
+
+	set := *s
+	set.schedule = sched
+	return &set, true
+}
+
+func (s *betweenSchedule) startTimes(start time.Time) (Schedule, bool) {
+	sched, ok := startTimes(s.schedule, start)
+	if !ok {
+		return s, false
+	}
+
+	started := *s
+	started.schedule = sched
+	return &started, true
+}
+
+func (s *betweenSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	sched, ok := withPrecision(s.schedule, precision)
+	if !ok {
+		return s, false
+	}
+
+	raised := *s
+	raised.schedule = sched
+	return &raised, true
+}
+
+// String returns the @between descriptor of the schedule, open sides of the window are
+// written as "*".
+func (s *betweenSchedule) String() string {
+	return fmt.Sprintf("@between %v %v %v", formatTime(s.start), formatTime(s.end), groups(s.schedule))
+}
+
+func (s *betweenSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *betweenSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *betweenSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first activation of the schedule after: after (field) in the window.
+//
+// returns the zero time if there is none.
+func (s *betweenSchedule) Next(after time.Time) time.Time {
+	var t time.Time
+	if after.Before(s.start) {
+		t = nextFrom(s.schedule, s.start)
+	} else {
+		t = nextAfter(s.schedule, after)
+	}
+
+	if !s.end.IsZero() && !t.Before(s.end) {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// TimesSchedule ------------------------------------------------------------------
+
+type timesSchedule struct {
+	// n is the number of activations.
+	n int
+
+	schedule Schedule
+
+	// start is the time the activations are counted from, the zero time until the
+	// schedule is added to a cronjob.
+	start time.Time
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+
+	// mu guards the activations the activations are counted on from.
+	mu sync.Mutex
+
+	// i is the number of the activation: prev (field), the last one found which wasnt
+	// after the time given to Next, prev (field) is the start if i is 0. upcoming (field)
+	// is the activation following it.
+	i              int
+	prev, upcoming time.Time
+}
+
+func (s *timesSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *timesSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *timesSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	sched, ok := withDSTPolicy(s.schedule, policy)
+	if !ok {
+		return s, false
+	}
+
+	return &timesSchedule{n: s.n, schedule: sched, start: s.start}, true
+}
+
+func (s *timesSchedule) startTimes(start time.Time) (Schedule, bool) {
+	sched, ok := startTimes(s.schedule, start)
+	if !ok && !s.start.IsZero() {
+		return s, false
+	}
+
+	if !s.start.IsZero() {
+		start = s.start
+	}
+	return &timesSchedule{n: s.n, schedule: sched, start: start}, true
+}
+
+func (s *timesSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	sched, ok := withPrecision(s.schedule, precision)
+	if !ok {
+		return s, false
+	}
+
+	return &timesSchedule{n: s.n, schedule: sched, start: s.start}, true
+}
+
+// String returns the @times descriptor of the schedule.
+func (s *timesSchedule) String() string {
+	return fmt.Sprintf("@times %v %v %v", s.n, formatTime(s.start), groups(s.schedule))
+}
+
+func (s *timesSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *timesSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *timesSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first of the first n activations after the start which is after:
+// after (field), the activations of a schedule which wasnt started are counted from
+// after (field).
+//
+// the activations are counted on from the last one found before after (field), the
+// times given to Next usually only grow.
+//
+// returns the zero time if there is none.
+func (s *timesSchedule) Next(after time.Time) time.Time {
+	if s.start.IsZero() {
+		next, _, _ := s.next(0, after, after)
+		return next
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, t := 0, s.start
+	if !s.upcoming.IsZero() && !after.Before(s.prev) {
+		if after.Before(s.upcoming) {
+			return s.upcoming
+		}
+		i, t = s.i+1, s.upcoming
+	}
+
+	next, i, t := s.next(i, t, after)
+	if !next.IsZero() {
+		s.i, s.prev, s.upcoming = i, t, next
+	}
+	return next
+}
+
+// next returns the first activation after: after (field), counting on from the
+// activation number i (field) at t (field). the number and time of the last activation
+// which isnt after after (field) are returned with it.
+//
+// returns the zero time if there is none.
+func (s *timesSchedule) next(i int, t, after time.Time) (time.Time, int, time.Time) {
+	for ; i < s.n; i++ {
+		next := nextAfter(s.schedule, t)
+		if next.IsZero() {
+			return time.Time{}, i, t
+		}
+
+		if next.After(after) {
+			return next, i, t
+		}
+		t = next
+	}
+
+	return time.Time{}, i, t
+}
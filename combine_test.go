@@ -0,0 +1,253 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCombinators(t *testing.T) {
+	// a saturday.
+	after := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		schedule func() Schedule
+		after    time.Time
+		expected []string
+	}{
+		{
+			name: "union",
+			schedule: func() Schedule {
+				return Union(spec("0 9 * * *")(), spec("0 17 * * *")())
+			},
+			after:    after,
+			expected: []string{"2026-10-17T17:00:00Z", "2026-10-18T09:00:00Z", "2026-10-18T17:00:00Z"},
+		},
+		{
+			name: "union shared activations",
+			schedule: func() Schedule {
+				return Union(spec("0 9 * * *")(), spec("0 9 * * *")())
+			},
+			after:    after,
+			expected: []string{"2026-10-18T09:00:00Z", "2026-10-19T09:00:00Z"},
+		},
+		{
+			name: "union constant",
+			schedule: func() Schedule {
+				return Union(At(after.Add(time.Hour)), spec("0 12 * * *")())
+			},
+			after:    after,
+			expected: []string{"2026-10-17T11:05:00Z", "2026-10-17T12:00:00Z", "2026-10-18T12:00:00Z"},
+		},
+		{
+			name: "intersect",
+			schedule: func() Schedule {
+				return Intersect(spec("*/15 * * * *")(), spec("* 9-16 * * 1-5")())
+			},
+			after:    after,
+			expected: []string{"2026-10-19T09:00:00Z", "2026-10-19T09:15:00Z", "2026-10-19T09:30:00Z"},
+		},
+		{
+			name: "intersect end of window",
+			schedule: func() Schedule {
+				return Intersect(spec("*/15 * * * *")(), spec("* 9-16 * * 1-5")())
+			},
+			after:    time.Date(2026, 10, 19, 16, 40, 0, 0, time.UTC),
+			expected: []string{"2026-10-19T16:45:00Z", "2026-10-20T09:00:00Z"},
+		},
+		{
+			name: "intersect nothing shared",
+			schedule: func() Schedule {
+				return Intersect(spec("0 0 * * *")(), spec("30 0 * * *")())
+			},
+			after:    after,
+			expected: []string{""},
+		},
+		{
+			name: "except",
+			schedule: func() Schedule {
+				return Except(spec("*/15 * * * *")(), spec("* * 25 12 *")())
+			},
+			after:    time.Date(2026, 12, 24, 23, 40, 0, 0, time.UTC),
+			expected: []string{"2026-12-24T23:45:00Z", "2026-12-26T00:00:00Z", "2026-12-26T00:15:00Z"},
+		},
+		{
+			name: "except everything",
+			schedule: func() Schedule {
+				return Except(spec("0 * * * *")(), spec("* * * * *")())
+			},
+			after:    after,
+			expected: []string{""},
+		},
+		{
+			name: "between",
+			schedule: func() Schedule {
+				return Between(after.Add(55*time.Minute), after.Add(115*time.Minute), spec("*/20 * * * *")())
+			},
+			after:    after,
+			expected: []string{"2026-10-17T11:00:00Z", "2026-10-17T11:20:00Z", "2026-10-17T11:40:00Z", ""},
+		},
+		{
+			name: "between open",
+			schedule: func() Schedule {
+				return Between(time.Time{}, time.Time{}, spec("0 0 * * *")())
+			},
+			after:    after,
+			expected: []string{"2026-10-18T00:00:00Z", "2026-10-19T00:00:00Z"},
+		},
+		{
+			name: "times",
+			schedule: func() Schedule {
+				return TimesFrom(2, after, spec("0 * * * *")())
+			},
+			after:    after,
+			expected: []string{"2026-10-17T11:00:00Z", "2026-10-17T12:00:00Z", ""},
+		},
+		{
+			name: "times constant",
+			schedule: func() Schedule {
+				return TimesFrom(2, after, At(after.Add(time.Hour)))
+			},
+			after:    after,
+			expected: []string{"2026-10-17T11:05:00Z", ""},
+		},
+		{
+			name: "times zero",
+			schedule: func() Schedule {
+				return TimesFrom(0, after, spec("0 * * * *")())
+			},
+			after:    after,
+			expected: []string{""},
+		},
+		{
+			name: "times in union",
+			schedule: func() Schedule {
+				return Union(TimesFrom(2, after, spec("30 * * * *")()), spec("0 * * * *")())
+			},
+			after:    after,
+			expected: []string{"2026-10-17T10:30:00Z", "2026-10-17T11:00:00Z", "2026-10-17T11:30:00Z", "2026-10-17T12:00:00Z", "2026-10-17T13:00:00Z"},
+		},
+		{
+			name: "nested",
+			schedule: func() Schedule {
+				return TimesFrom(3, after, Except(
+					Intersect(spec("*/15 * * * *")(), spec("* 9-16 * * 1-5")()),
+					spec("* 9 * * *")(),
+				))
+			},
+			after:    after,
+			expected: []string{"2026-10-19T10:00:00Z", "2026-10-19T10:15:00Z", "2026-10-19T10:30:00Z", ""},
+		},
+	}
+
+	for _, c := range cases {
+		sched := c.schedule()
+
+		after := c.after
+		for i, expected := range c.expected {
+			got := activate(sched, after)
+			if want := mustParseTime(t, expected); !got.Equal(want) {
+				t.Fatalf("%v: activation %v: got: %v want: %v", c.name, i, got, want)
+			}
+			after = got
+		}
+	}
+}
+
+func TestCombinatorsDSTPolicy(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sched := WithDSTPolicy(Union(spec("CRON_TZ=America/New_York 30 2 * * *")()), DSTSkip)
+
+	// 02:30 is skipped on 2026-03-08.
+	got := activate(sched, time.Date(2026, 3, 7, 12, 0, 0, 0, newYork))
+	if want := time.Date(2026, 3, 9, 2, 30, 0, 0, newYork); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestTimesStart(t *testing.T) {
+	after := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+	sched := Union(Times(2, spec("0 * * * *")()))
+
+	started, ok := startTimes(sched, after)
+	if !ok {
+		t.Fatal("got: no Times started want: a Times started")
+	}
+
+	// the copy counts from the start, the schedule passed from the time given to Next.
+	later := after.Add(2*time.Hour + 25*time.Minute)
+	if got := AsNext(started).Next(later); !got.IsZero() {
+		t.Fatalf("got: %v want: no activation", got)
+	}
+	if got, want := AsNext(sched).Next(later), mustParseTime(t, "2026-10-17T13:00:00Z"); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// a started Times keeps its start.
+	if _, ok := startTimes(started, later); ok {
+		t.Fatal("got: started again want: the start kept")
+	}
+
+	// the jobs count from when they are added.
+	c := New()
+	added := c.Now()
+	c.AddFunc(func() error { return nil }, sched)
+
+	times := c.Jobs()[0].Schedule.(*unionSchedule).schedules[0].(*timesSchedule)
+	if times.start.Before(added) {
+		t.Fatalf("got: %v want: a start from %v", times.start, added)
+	}
+	if start := sched.(*unionSchedule).schedules[0].(*timesSchedule).start; !start.IsZero() {
+		t.Fatalf("got: %v want: no start", start)
+	}
+}
+
+// countingSchedule runs every minute, counting the calls to Next.
+type countingSchedule struct {
+	calls int
+}
+
+func (s *countingSchedule) Next(after time.Time) time.Time {
+	s.calls++
+	return after.Truncate(time.Minute).Add(time.Minute)
+}
+
+func TestTimesNextResumes(t *testing.T) {
+	after := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+	counter := &countingSchedule{}
+	sched := TimesFrom(1000, after, FromNext(counter)).(NextSchedule)
+
+	// each activation is found once when moving through them.
+	next := after
+	for i := 0; i < 1000; i++ {
+		next = sched.Next(next)
+	}
+	if want := after.Add(1000 * time.Minute); !next.Equal(want) {
+		t.Fatalf("got: %v want: %v", next, want)
+	}
+	if got, want := counter.calls, 1000; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// looking back counts from the start again.
+	if got, want := sched.Next(after), after.Add(time.Minute); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+// activate moves the cyclic schedule (field) the way the scheduler does and returns its
+// next activation after: after (field), the zero time if there is none.
+func activate(schedule Schedule, after time.Time) time.Time {
+	schedule.(CyclicSchedule).MoveNextAvtivation(after)
+
+	d := schedule.Calculate(after)
+	if d == never {
+		return time.Time{}
+	}
+
+	return after.Add(d)
+}
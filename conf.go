@@ -29,6 +29,30 @@ func WithLocation(loc *time.Location) CronJobConf {
 	}
 }
 
+// WithHighPrecision puts cronjob in high precision mode, allowing schedules with
+// intervals down to a millisecond instead of a second.
+func WithHighPrecision() CronJobConf {
+	return func(cj *CronJob) {
+		cj.precision = highPrecision
+	}
+}
+
+// WithScheduler sets the scheduler used by cronjob, see NewHeapScheduler and
+// NewWheelScheduler.
+func WithScheduler(scheduler Scheduler) CronJobConf {
+	return func(cj *CronJob) {
+		cj.scheduler = scheduler
+	}
+}
+
+// WithStore keeps the jobs added with AddNamedFunc in store (field), the jobs stored
+// by a previous process are added back on start, see FileStore.
+func WithStore(store Store) CronJobConf {
+	return func(cj *CronJob) {
+		cj.store = store
+	}
+}
+
 // JobConf represents a function to configure the behaviour of a job.
 type JobConf func(*Job)
 
@@ -51,3 +75,19 @@ func WithChain(chain Chain) JobConf {
 		j.chain = chain
 	}
 }
+
+// WithRetry retries the job decorated with its chains max (field) times with a timeout (field)
+// delay, like Retry added first in the chain.
+//
+// timeouts are raised to the precision of the cronjob, a second or a millisecond in high
+// precision mode (see WithHighPrecision).
+func WithRetry(timeout time.Duration, max int) JobConf {
+	return func(j *Job) {
+		if max <= 0 {
+			max = 1
+		}
+
+		j.retryTimeout = timeout
+		j.retryMax = max
+	}
+}
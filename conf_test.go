@@ -3,6 +3,7 @@ package cronjob
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"log"
 	"sync"
 	"testing"
@@ -59,6 +60,29 @@ func TestWithLocation(t *testing.T) {
 	}
 }
 
+func TestWithScheduler(t *testing.T) {
+	t.Parallel()
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	cron := New(WithScheduler(NewHeapScheduler()))
+	cron.AddFunc(func() error { wg.Done(); return nil }, In(cron.Now(), time.Second))
+	cron.Start()
+	defer cron.Stop()
+	cron.AddFunc(func() error { wg.Done(); return nil }, In(cron.Now(), time.Second))
+
+	select {
+	case <-wait(wg):
+		// jobs ran.
+	case <-time.After(3 * time.Second):
+		t.Fatal("no job ran.")
+	}
+
+	if got, want := len(cron.Jobs()), 0; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
 /* Job Confs --------------------------------------------------------------------------- */
 
 func TestWithChain(t *testing.T) {
@@ -87,6 +111,50 @@ func TestWithChain(t *testing.T) {
 	}
 }
 
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+	var count, seen int
+
+	wantErr := fmt.Errorf("error")
+	job := &Job{
+		job: func() error {
+			count++
+			return wantErr
+		},
+		precision: highPrecision,
+	}
+	for _, conf := range []JobConf{
+		WithRetry(0, 4),
+		WithChain(NewChain(func(fj FuncJob) FuncJob {
+			return func() error {
+				// the decorators see the errors of the job.
+				if err := fj(); err == wantErr {
+					seen++
+				}
+				return wantErr
+			}
+		})),
+	} {
+		conf(job)
+	}
+
+	// the timeout is raised to the precision of the job instead of a second.
+	start := time.Now()
+	if err := job.Run(); err != wantErr {
+		t.Fatalf("got: %v want: %v", err, wantErr)
+	}
+
+	if got, want := time.Since(start), time.Second; got >= want {
+		t.Fatalf("got: %v want: under %v", got, want)
+	}
+	if got, want := count, 4; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := seen, 4; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
 func TestWithRunOnStart(t *testing.T) {
 	t.Parallel()
 	t.Run("Without Chains", func(t *testing.T) {
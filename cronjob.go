@@ -5,7 +5,6 @@ import (
 	"log"
 	"os"
 	"sync"
-	"sync/atomic"
 	"time"
 )
 
@@ -15,10 +14,16 @@ type CronJob struct {
 	verbose   bool
 	idCount   int
 	location  *time.Location
+	precision time.Duration
 	add       chan *Node
 	remove    chan int
+	trigger   chan *Node
+	triggers  map[int]*Node
+	outcomes  chan outcome
 	stop      chan struct{}
 	nodes     chan chan []*Node
+	store     Store
+	restored  bool
 	runningMu sync.Mutex
 	isRunning bool
 }
@@ -28,6 +33,9 @@ type Schedule interface {
 	Calculate(time.Time) time.Duration
 }
 
+// CyclicSchedule is implemented by schedules which run more than once.
+//
+// schedules which also implement NextSchedule arent moved by the scheduler.
 type CyclicSchedule interface {
 	// MoveNextAvtivation re-calculates the next time the schedule will be activated
 	// at.
@@ -36,11 +44,46 @@ type CyclicSchedule interface {
 	Schedule
 }
 
+// NextSchedule is implemented by schedules which compute their activations without
+// being moved, all the built-in schedules implement it.
+//
+// the scheduler keeps the time of the next activation of each job, a NextSchedule can be
+// shared by jobs and previewed while in use.
+type NextSchedule interface {
+	// Next returns the first activation after the time provided, the zero time if there
+	// are no further activations.
+	Next(time.Time) time.Time
+}
+
+// ObservingSchedule is implemented by schedules which adapt to the outcome of their
+// job.
+//
+// the scheduler re-calculates the next activation of the job once it is observed.
+type ObservingSchedule interface {
+	// Observe is called with the error returned by the job after each run.
+	Observe(error)
+
+	Schedule
+}
+
+// jobSchedule is implemented by schedules holding the state of a single job, the cronjob
+// keeps a copy of the schedule for each job so it can be shared.
+type jobSchedule interface {
+	// forJob returns a copy of the schedule without the state of other jobs.
+	forJob() Schedule
+}
+
+// Scheduler keeps the nodes of the cronjob ordered by their next activation, see
+// WithScheduler.
+//
+// the cronjob calls the scheduler from a single goroutine at a time, implementations
+// dont need to be safe for concurrent use. use the package schedulertest to test them.
 type Scheduler interface {
-	// NextCycle returns the duration to sleep before next activation cycle.
+	// NextCycle returns the duration to sleep before next activation cycle, which
+	// doesnt go past the earliest activation. -1 if empty.
 	NextCycle(time.Time) time.Duration
 
-	// RunNow returns the jobs that need to be ran now and cleans the scheduler.
+	// RunNow returns the jobs that need to be ran now, they are kept until cleaned.
 	RunNow(time.Time) []*Node
 
 	// GetAll returns all the nodes in the scheduler.
@@ -64,6 +107,20 @@ type Job struct {
 	chain Chain
 
 	runOnStart bool
+
+	// retryTimeout and retryMax are the timeout and tries of WithRetry, no retries if
+	// retryMax is 0.
+	retryTimeout time.Duration
+	retryMax     int
+
+	// precision is the precision of the cronjob running the job, the shortest timeout of
+	// WithRetry.
+	precision time.Duration
+
+	// name and args are the registered function and its arguments of the jobs added
+	// with AddNamedFunc.
+	name string
+	args []string
 }
 
 func New(confs ...CronJobConf) *CronJob {
@@ -71,8 +128,12 @@ func New(confs ...CronJobConf) *CronJob {
 		scheduler: &linkedList{},
 		logger:    log.New(os.Stdout, "[CronJob]", log.Flags()),
 		location:  time.Local,
+		precision: defaultPrecision,
 		add:       make(chan *Node),
 		remove:    make(chan int),
+		trigger:   make(chan *Node),
+		triggers:  make(map[int]*Node),
+		outcomes:  make(chan outcome),
 		stop:      make(chan struct{}),
 		nodes:     make(chan chan []*Node),
 	}
@@ -80,6 +141,10 @@ func New(confs ...CronJobConf) *CronJob {
 	for _, conf := range confs {
 		conf(cronJob)
 	}
+
+	if sched, ok := cronJob.scheduler.(precisionScheduler); ok {
+		sched.setPrecision(cronJob.precision)
+	}
 	return cronJob
 }
 
@@ -95,10 +160,38 @@ func (c *CronJob) Now() time.Time {
 //	(*CronJob).AddFunc(foo, cronjob.In(time.Now(), 4 * time.Hour))
 //
 // will schedule foo to run in 4 hours from time.Now()
+//
+// jobs added with a @reboot schedule run the same way as with WithRunOnStart.
 func (c *CronJob) AddFunc(cmd FuncJob, schedule Schedule, confs ...JobConf) int {
 	return c.addJob(&Job{job: cmd}, schedule, confs...)
 }
 
+// AddNamedFunc adds the function registered with name (field) to the execution cycle,
+// called with args (field). the job is kept in the store of the instance (see WithStore)
+// until it has no further activations or is removed.
+//
+// can be called after starting the execution cycle or before.
+//
+//	(*CronJob).AddNamedFunc("send-reminder", cronjob.In(time.Now(), 4 * time.Hour), "bob")
+//
+// will schedule the function registered as "send-reminder" to run with "bob" in 4 hours
+// from time.Now(), even if the process restarted in between.
+//
+// returns an error if no function is registered with name (field) or if the schedule
+// cant be marshalled, see RegisterJob and RegisterSchedule.
+func (c *CronJob) AddNamedFunc(name string, schedule Schedule, args ...string) (int, error) {
+	job, err := namedJob(name, args)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := marshalText(schedule); err != nil {
+		return 0, err
+	}
+
+	return c.addJob(job, schedule), nil
+}
+
 // RemoveJob removes the job with id: id (field). (no-op if job not found)
 //
 // can be called after starting the execution cycle or before.
@@ -107,7 +200,10 @@ func (c *CronJob) RemoveJob(id int) {
 	defer c.runningMu.Unlock()
 
 	if !c.isRunning {
+		c.restore()
 		c.scheduler.RemoveNode(id)
+		delete(c.triggers, id)
+		c.unpersist(id)
 	} else {
 		c.remove <- id
 	}
@@ -127,6 +223,7 @@ func (c *CronJob) Start() {
 	if c.isRunning {
 		return
 	}
+	c.restore()
 	c.isRunning = true
 	go c.run()
 }
@@ -163,8 +260,8 @@ func (c *CronJob) StopWithFlush() context.Context {
 	c.isRunning = false
 	c.runningMu.Unlock()
 
-	// run jobs.
-	nodes := c.Jobs()
+	// run jobs, trigger schedules which didnt fire have nothing to run.
+	nodes := c.scheduler.GetAll()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	if len(nodes) == 0 { // no nodes.
@@ -172,19 +269,26 @@ func (c *CronJob) StopWithFlush() context.Context {
 		return ctx
 	}
 
-	var runningWorkerCount int64 = int64(len(nodes))
+	wg := &sync.WaitGroup{}
+	wg.Add(len(nodes))
 	for _, node := range nodes {
 		go func(node *Node) {
 			node.Job.Run()
-			c := atomic.AddInt64(&runningWorkerCount, -1)
-			if c == 0 { // last job, cancel.
-				cancel()
-			}
+			wg.Done()
 		}(node)
 	}
 
+	// cancel when the last job finishes.
+	go func() {
+		wg.Wait()
+		cancel()
+	}()
+
 	// clean nodes.
 	c.scheduler.Clean(c.Now(), nodes)
+	for _, node := range nodes {
+		c.persist(node)
+	}
 
 	return ctx
 }
@@ -197,12 +301,13 @@ func (c *CronJob) Run() {
 	if c.isRunning {
 		return
 	}
+	c.restore()
 	c.isRunning = true
 	c.runningMu.Unlock()
 	c.run()
 }
 
-// Jobs returns the current nodes which are registered to the scheduler.
+// Jobs returns copies of the current nodes which are registered to the scheduler.
 func (c *CronJob) Jobs() []*Node {
 	c.runningMu.Lock()
 	defer c.runningMu.Unlock()
@@ -213,10 +318,38 @@ func (c *CronJob) Jobs() []*Node {
 
 		return <-replyChan
 	} else {
-		return c.scheduler.GetAll()
+		return c.allNodes()
 	}
 }
 
+// allNodes returns copies of the nodes of the scheduler and of the nodes of the trigger
+// schedules which didnt fire, the copies can be read while the processing thread runs.
+func (c *CronJob) allNodes() []*Node {
+	nodes := c.scheduler.GetAll()
+
+	scheduled := make(map[int]bool, len(nodes))
+	for _, node := range nodes {
+		scheduled[node.Id] = true
+	}
+	for id, node := range c.triggers {
+		if !scheduled[id] {
+			nodes = append(nodes, node)
+		}
+	}
+
+	copies := make([]*Node, len(nodes))
+	for i, node := range nodes {
+		copies[i] = &Node{
+			Id:       node.Id,
+			Schedule: node.Schedule,
+			Job:      node.Job,
+			NextRun:  node.NextRun,
+		}
+	}
+
+	return copies
+}
+
 func (c *CronJob) addJob(job *Job, schedule Schedule, confs ...JobConf) int {
 	c.runningMu.Lock()
 	defer c.runningMu.Unlock()
@@ -224,45 +357,110 @@ func (c *CronJob) addJob(job *Job, schedule Schedule, confs ...JobConf) int {
 	for _, conf := range confs {
 		conf(job)
 	}
+	job.precision = c.precision
+	schedule, _ = withPrecision(schedule, c.precision)
+	schedule, _ = startTimes(schedule, c.Now())
+
+	// the ids of the stored jobs are taken before adding others.
+	if !c.isRunning {
+		c.restore()
+	}
+
+	// @reboot schedules run on start, the ones which only run on start arent added.
+	reboot, rebootOnly := rebootOf(schedule)
 
 	// add a job which will be ran on the first execution cycle (negative time.Duration).
-	if job.runOnStart {
+	if job.runOnStart || reboot {
 		node := &Node{
-			Schedule: &constantSchedule{c.Now().Add(-1 * time.Second)},
+			Id:       c.nextId(),
+			Schedule: &constantSchedule{at: c.Now().Add(-1 * time.Second)},
 			Job:      job,
 		}
 
 		if c.isRunning {
 			go job.Run()
 		} else {
-			c.idCount++
-
-			node.Id = c.idCount
 			c.scheduler.AddNode(c.Now(), node)
 		}
+
+		if rebootOnly {
+			return node.Id
+		}
 	}
 
-	c.idCount++
 	node := &Node{
-		Id:       c.idCount,
+		Id:       c.nextId(),
 		Schedule: schedule,
 		Job:      job,
 	}
 	if c.isRunning {
 		c.add <- node
 	} else {
-		c.scheduler.AddNode(c.Now(), node)
+		c.addNode(c.Now(), node)
+		c.persist(node)
 	}
 	return node.Id
 }
 
+// addNode adds the node (field) to the scheduler, nodes with a trigger schedule are kept
+// to be watched.
+//
+// reports whether the node has a trigger schedule.
+func (c *CronJob) addNode(now time.Time, node *Node) bool {
+	if sched, ok := node.Schedule.(jobSchedule); ok {
+		node.Schedule = sched.forJob()
+	}
+
+	if _, ok := node.Schedule.(*triggerSchedule); ok {
+		c.triggers[node.Id] = node
+		return true
+	}
+
+	c.scheduler.AddNode(now, node)
+	return false
+}
+
+// watch watches the events of the trigger schedule of node (field), sending the node to
+// the processing thread when they fire.
+//
+// the returned channel stops watching when closed.
+func (c *CronJob) watch(node *Node) chan struct{} {
+	stop := make(chan struct{})
+
+	go node.Schedule.(*triggerSchedule).watch(stop, func() {
+		select {
+		case c.trigger <- node:
+		case <-stop:
+		}
+	})
+
+	return stop
+}
+
 func (c *CronJob) run() {
 	c.logger.Println("starting processing thread")
 	now := c.Now()
 
+	// watch the trigger schedules while running.
+	watchers := make(map[int]chan struct{}, len(c.triggers))
+	for id, node := range c.triggers {
+		watchers[id] = c.watch(node)
+	}
+
+	// done stops the jobs from sending their outcome once stopped.
+	done := make(chan struct{})
+	defer func() {
+		for _, stop := range watchers {
+			close(stop)
+		}
+		close(done)
+	}()
+
 	for {
+		// sleep until the next activation from the current time, the time taken to run
+		// the previous cycle doesnt delay it.
 		var timer *time.Timer
-		if sleep := c.scheduler.NextCycle(now); sleep >= 0 {
+		if sleep := c.scheduler.NextCycle(c.Now()); sleep >= 0 {
 			timer = time.NewTimer(sleep)
 		} else {
 			timer = time.NewTimer(1000000 * time.Hour)
@@ -276,29 +474,69 @@ func (c *CronJob) run() {
 				// run all jobs.
 				nodes := c.scheduler.RunNow(now)
 				for _, node := range nodes {
-					go node.Job.Run()
+					if sched, ok := node.Schedule.(*triggerSchedule); ok {
+						sched.ran(now)
+					}
+					go c.runJob(node, done)
 				}
 
 				// clean nodes after running.
 				c.scheduler.Clean(now, nodes)
+				for _, node := range nodes {
+					c.persist(node)
+				}
 				c.logDebugf("woke up at: %v\n", woke)
 
 			case reply := <-c.nodes:
-				reply <- c.scheduler.GetAll()
+				reply <- c.allNodes()
 				continue // no need to re-calc timer.
 
 			case node := <-c.add:
 				timer.Stop()
 				now = c.Now()
 
-				c.scheduler.AddNode(now, node)
+				if c.addNode(now, node) {
+					watchers[node.Id] = c.watch(node)
+				}
+				c.persist(node)
 				c.logDebugf("added new node with id: %v\n", node.Id)
 
+			case node := <-c.trigger:
+				// the node was removed while its event fired.
+				if _, ok := c.triggers[node.Id]; !ok {
+					continue
+				}
+
+				timer.Stop()
+				now = c.Now()
+
+				// move the node to the run of the fired events.
+				node.Schedule.(*triggerSchedule).fire(now)
+				c.scheduler.RemoveNode(node.Id)
+				c.scheduler.AddNode(now, node)
+				c.persist(node)
+				c.logDebugf("triggered node with id: %v\n", node.Id)
+
+			case o := <-c.outcomes:
+				timer.Stop()
+				now = c.Now()
+
+				o.node.Schedule.(ObservingSchedule).Observe(o.err)
+				c.reschedule(now, o.node)
+				c.persist(o.node)
+				c.logDebugf("observed outcome of node with id: %v\n", o.node.Id)
+
 			case id := <-c.remove:
 				timer.Stop()
 				now = c.Now()
 
 				c.scheduler.RemoveNode(id)
+				if stop, ok := watchers[id]; ok {
+					close(stop)
+					delete(watchers, id)
+					delete(c.triggers, id)
+				}
+				c.unpersist(id)
 				c.logDebugf("attempting to remove node with id: %v\n", id)
 
 			case <-c.stop:
@@ -313,6 +551,168 @@ func (c *CronJob) run() {
 	}
 }
 
+// outcome is the error returned by the job of a node.
+type outcome struct {
+	node *Node
+	err  error
+}
+
+// runJob runs the job of node (field), sending its outcome to the processing thread if
+// the schedule observes it, until done (field) is closed.
+func (c *CronJob) runJob(node *Node, done <-chan struct{}) {
+	err := node.Job.Run()
+	if _, ok := node.Schedule.(ObservingSchedule); !ok {
+		return
+	}
+
+	select {
+	case c.outcomes <- outcome{node, err}:
+	case <-done:
+	}
+}
+
+// nodeFinder is implemented by schedulers finding a node by id without going through all
+// of them.
+type nodeFinder interface {
+	node(id int) *Node
+}
+
+// idAllocator is implemented by schedulers shared by several cronjobs, giving the ids
+// of their nodes.
+type idAllocator interface {
+	nextId() (int, error)
+}
+
+// precisionScheduler is implemented by schedulers reading the jobs added by other
+// cronjobs, run with the precision of the cronjob.
+type precisionScheduler interface {
+	setPrecision(time.Duration)
+}
+
+// nextId returns the id of the next node added, taken from the scheduler if it gives
+// them.
+func (c *CronJob) nextId() int {
+	if allocator, ok := c.scheduler.(idAllocator); ok {
+		id, err := allocator.nextId()
+		if err == nil {
+			return id
+		}
+		c.logger.Printf("cant get the next id: %v\n", err)
+	}
+
+	c.idCount++
+	return c.idCount
+}
+
+// scheduled returns the node of the scheduler with the given id, nil if not found.
+func (c *CronJob) scheduled(id int) *Node {
+	if finder, ok := c.scheduler.(nodeFinder); ok {
+		return finder.node(id)
+	}
+
+	for _, node := range c.scheduler.GetAll() {
+		if node.Id == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// reschedule re-calculates the next activation of the node (field) from now (field).
+//
+// no-op if the node isnt in the scheduler anymore.
+func (c *CronJob) reschedule(now time.Time, node *Node) {
+	if c.scheduled(node.Id) == node {
+		c.scheduler.RemoveNode(node.Id)
+		c.scheduler.AddNode(now, node)
+	}
+}
+
+// restore adds the jobs of the store to the scheduler, once.
+//
+// jobs whose function isnt registered are left in the store. cyclic jobs which missed
+// an activation while the process was stopped run once straight away.
+func (c *CronJob) restore() {
+	if c.store == nil || c.restored {
+		return
+	}
+	c.restored = true
+
+	jobs, err := c.store.Load()
+	if err != nil {
+		c.logger.Printf("cant load the stored jobs: %v\n", err)
+		return
+	}
+
+	// the ids of all the stored jobs are taken before adding any node, the runs of the
+	// missed activations get new ids.
+	for _, stored := range jobs {
+		if stored.Id > c.idCount {
+			c.idCount = stored.Id
+		}
+	}
+
+	now := c.Now()
+	for _, stored := range jobs {
+		job, err := namedJob(stored.Name, stored.Args)
+		if err != nil || stored.Schedule.Schedule == nil {
+			c.logger.Printf("cant restore job with id: %v: %v\n", stored.Id, err)
+			continue
+		}
+		job.precision = c.precision
+
+		schedule, _ := withPrecision(stored.Schedule.Schedule, c.precision)
+		schedule, _ = startTimes(schedule, now)
+		node := &Node{
+			Id:       stored.Id,
+			Schedule: schedule,
+			Job:      job,
+		}
+		c.addNode(now, node)
+
+		_, cyclic := node.Schedule.(CyclicSchedule)
+		if missed := stored.NextRun; cyclic && !missed.IsZero() && !missed.After(now) {
+			c.scheduler.AddNode(now, &Node{
+				Id:       c.nextId(),
+				Schedule: &constantSchedule{at: missed},
+				Job:      job,
+			})
+		}
+
+		c.persist(node)
+	}
+}
+
+// persist saves the node (field) of a job added with AddNamedFunc in the store, or
+// deletes it if the node left the scheduler.
+func (c *CronJob) persist(node *Node) {
+	if c.store == nil || node.Job == nil || node.Job.name == "" {
+		return
+	}
+
+	var err error
+	if c.scheduled(node.Id) == node || c.triggers[node.Id] == node {
+		err = c.store.Save(storedJob(node))
+	} else {
+		err = c.store.Delete(node.Id)
+	}
+
+	if err != nil {
+		c.logger.Printf("cant store job with id: %v: %v\n", node.Id, err)
+	}
+}
+
+// unpersist deletes the job with the given id from the store.
+func (c *CronJob) unpersist(id int) {
+	if c.store == nil {
+		return
+	}
+
+	if err := c.store.Delete(id); err != nil {
+		c.logger.Printf("cant delete stored job with id: %v: %v\n", id, err)
+	}
+}
+
 func (c *CronJob) logDebugf(format string, v ...interface{}) {
 	if c.verbose {
 		c.logger.Printf(format, v...)
@@ -320,6 +720,13 @@ func (c *CronJob) logDebugf(format string, v ...interface{}) {
 }
 
 // Run runs the function provided to job with the chains.
-func (j *Job) Run() {
-	j.chain.Run(j.job)
+//
+// returns the error of the job, observed by schedules implementing ObservingSchedule.
+func (j *Job) Run() error {
+	chain := j.chain
+	if j.retryMax > 0 {
+		chain = MergeChains(NewChain(retry(j.retryTimeout, j.retryMax, j.precision)), chain)
+	}
+
+	return chain.Run(j.job)
 }
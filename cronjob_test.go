@@ -3,6 +3,7 @@ package cronjob
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -46,6 +47,91 @@ func TestAddFunc(t *testing.T) {
 	})
 }
 
+func TestAddFuncReboot(t *testing.T) {
+	t.Parallel()
+	var count int32
+
+	c := New()
+	sched, err := Parse("@reboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.AddFunc(func() error { atomic.AddInt32(&count, 1); return nil }, sched)
+	c.Start()
+	defer c.Stop()
+
+	time.Sleep(2 * time.Second)
+	if got, want := atomic.LoadInt32(&count), int32(1); got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	if got, want := len(c.Jobs()), 0; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestAddFuncRebootWrapped(t *testing.T) {
+	t.Parallel()
+	reboot, err := Parse("@reboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		schedule Schedule
+		jobs     int
+	}{
+		{"location", InLocation(reboot, time.UTC), 0},
+		{"union", Union(reboot, Every(time.Hour)), 1},
+		{"union of reboots", Union(reboot, InLocation(reboot, time.UTC)), 0},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			var count int32
+
+			cron := New()
+			cron.AddFunc(func() error { atomic.AddInt32(&count, 1); return nil }, c.schedule)
+			cron.Start()
+			defer cron.Stop()
+
+			time.Sleep(2 * time.Second)
+			if got, want := atomic.LoadInt32(&count), int32(1); got != want {
+				t.Fatalf("got: %v want: %v", got, want)
+			}
+			if got, want := len(cron.Jobs()), c.jobs; got != want {
+				t.Fatalf("got: %v want: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAddFuncRebootWhileRunning(t *testing.T) {
+	t.Parallel()
+	sched, err := Parse("@reboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.Start()
+	defer c.Stop()
+
+	ran := make(chan struct{}, 1)
+	if id := c.AddFunc(func() error { ran <- struct{}{}; return nil }, sched); id == 0 {
+		t.Fatalf("got: %v want: an id", id)
+	}
+
+	select {
+	case <-ran:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no job ran.")
+	}
+}
+
 func TestRemoveJob(t *testing.T) {
 	t.Parallel()
 	t.Run("While Running", func(t *testing.T) {
@@ -214,6 +300,26 @@ func TestStopWithFlushWait(t *testing.T) {
 	})
 }
 
+func TestJobsWhileRunning(t *testing.T) {
+	t.Parallel()
+	var count int32
+
+	c := New(WithHighPrecision())
+	c.AddFunc(func() error { atomic.AddInt32(&count, 1); return nil }, Every(time.Millisecond))
+	c.AddFunc(func() error { return nil }, Every(time.Hour))
+	c.Start()
+	defer c.Stop()
+
+	// the nodes are read while the processing thread moves them, run with -race.
+	for atomic.LoadInt32(&count) < 20 {
+		for _, node := range c.Jobs() {
+			if node.NextRun.IsZero() {
+				t.Fatalf("got: zero next run want: next run of job %v", node.Id)
+			}
+		}
+	}
+}
+
 func wait(wg *sync.WaitGroup) <-chan struct{} {
 	ch := make(chan struct{}, 1)
 	go func() {
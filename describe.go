@@ -0,0 +1,469 @@
+package cronjob
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Locale holds the phrases used to describe schedules in a language, keyed by their
+// english phrase. the phrases are fmt formats with the same verbs as their english
+// phrase, phrases missing from the locale are left in english.
+//
+// example:
+//
+//	cronjob.RegisterLocale("fr", cronjob.Locale{
+//		"every day":     "tous les jours",
+//		"every weekday": "tous les jours de semaine",
+//		"at %v":         "à %v",
+//	})
+//
+// the english phrases are listed in EnglishPhrases.
+type Locale map[string]string
+
+// EnglishPhrases are the phrases used to describe schedules, the keys of a Locale.
+var EnglishPhrases = []string{
+	"every day", "every weekday", "every weekend day", "every %v", "on day %v of the month",
+	"on days %v of the month", "on the last day of the month", "%v and %v", "%v, %v",
+	"%v, in %v", "at %v", "every second", "every minute", "every %v seconds",
+	"every %v minutes", "at second %v", "at minute %v", "at seconds %v", "at minutes %v",
+	"of every hour", "between %v and %v", "during hours %v", "%v %v", "once at %v",
+	"when the cronjob starts", "as the recurrence rule %v", "as the ISO 8601 interval %v",
+	"as the systemd calendar event %v", "at %v at %v", "%v after %v at %v",
+	"%v before %v at %v", "%v or %v", "%v, except %v", "%v, from %v until %v",
+	"%v, from %v", "%v, until %v", "%v, %v times", "%v, delayed by up to %v",
+	"%v, on business days", "%v, moved to the next business day",
+	"%v, moved to the previous business day", "when triggered", "%v, debounced by %v",
+	"%v, at most once every %v", "every %v, backing off up to %v while idle",
+	"sunday", "monday", "tuesday",
+	"wednesday", "thursday", "friday", "saturday", "january", "february", "march",
+	"april", "may", "june", "july", "august", "september", "october", "november",
+	"december", "on %v of the month", "the %v %v", "the last %v", "first", "second",
+	"third", "fourth", "fifth", "sunrise", "sunset", "solar noon", "civil dawn", "civil dusk",
+	"nautical dawn", "nautical dusk", "astronomical dawn", "astronomical dusk",
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]Locale{
+		"en": {},
+	}
+)
+
+// RegisterLocale registers the phrases: locale (field) of the language: name (field),
+// used by Describe.
+func RegisterLocale(name string, locale Locale) {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+
+	locales[name] = locale
+}
+
+// Describe returns a description of schedule (field) in the language: locale (field),
+// english ("en") if the language isnt registered.
+//
+// example:
+//
+//	sched, _ := cronjob.Parse("CRON_TZ=Europe/Paris 0 9 * * 1-5")
+//	cronjob.Describe(sched, "en")
+//
+// returns "every weekday at 09:00 Europe/Paris". schedules which arent built-in are
+// described by their String method, if they have one.
+func Describe(schedule Schedule, locale string) string {
+	localesMu.RLock()
+	phrases := locales[locale]
+	localesMu.RUnlock()
+
+	d := describer{phrases}
+	return d.describe(schedule)
+}
+
+// describer describes schedules with the phrases of a locale.
+type describer struct {
+	phrases Locale
+}
+
+// tr formats the args (field) with the phrase: phrase (field) of the locale.
+func (d describer) tr(phrase string, args ...interface{}) string {
+	if translated, ok := d.phrases[phrase]; ok {
+		phrase = translated
+	}
+
+	return fmt.Sprintf(phrase, args...)
+}
+
+// list joins the items: items (field) with commas and "and".
+func (d describer) list(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+
+	head := items[0]
+	for _, item := range items[1 : len(items)-1] {
+		head = d.tr("%v, %v", head, item)
+	}
+
+	return d.tr("%v and %v", head, items[len(items)-1])
+}
+
+func (d describer) describe(schedule Schedule) string {
+	switch s := schedule.(type) {
+	case *specSchedule:
+		return d.withLocation(d.spec(s), s.location)
+	case *constantSchedule:
+		return d.tr("once at %v", s.at.Format("2006-01-02 15:04:05 MST"))
+	case *rebootSchedule:
+		return d.tr("when the cronjob starts")
+	case *cyclicSchedule:
+		return d.tr("every %v", shortDuration(s.every))
+	case *fixedCyclicSchedule:
+		return d.tr("every %v", shortDuration(s.every))
+	case *locationSchedule:
+		return d.withLocation(d.describe(s.schedule), s.location)
+	case *cyclicLocationSchedule:
+		return d.withLocation(d.describe(s.schedule), s.location)
+	case *rruleSchedule:
+		return d.tr("as the recurrence rule %v", s.source)
+	case *isoSchedule:
+		return d.tr("as the ISO 8601 interval %v", s.source)
+	case *systemdSchedule:
+		return d.tr("as the systemd calendar event %v", s.source)
+	case *solarSchedule:
+		return d.solar(s)
+	case *unionSchedule:
+		return d.join("%v or %v", s.schedules)
+	case *intersectSchedule:
+		return d.join("%v and %v", s.schedules)
+	case *exceptSchedule:
+		return d.tr("%v, except %v", d.describe(s.base), d.describe(s.exclusion))
+	case *betweenSchedule:
+		return d.between(s)
+	case *timesSchedule:
+		return d.tr("%v, %v times", d.describe(s.schedule), s.n)
+	case *jitterSchedule:
+		return d.tr("%v, delayed by up to %v", d.describe(s.schedule), shortDuration(s.max))
+	case *businessDaysSchedule:
+		return d.tr("%v, on business days", d.describe(s.schedule))
+	case *rollSchedule:
+		if s.forward {
+			return d.tr("%v, moved to the next business day", d.describe(s.schedule))
+		}
+		return d.tr("%v, moved to the previous business day", d.describe(s.schedule))
+	case *triggerSchedule:
+		return d.trigger(s)
+	case *backoffSchedule:
+		return d.tr("every %v, backing off up to %v while idle", shortDuration(s.base), shortDuration(s.max))
+	case *nextSchedule:
+		return fmt.Sprint(s.schedule)
+	default:
+		return fmt.Sprint(s)
+	}
+}
+
+// trigger describes the trigger schedule: s (field).
+func (d describer) trigger(s *triggerSchedule) string {
+	description := d.tr("when triggered")
+	if s.debounce > 0 {
+		description = d.tr("%v, debounced by %v", description, shortDuration(s.debounce))
+	}
+	if s.throttle > 0 {
+		description = d.tr("%v, at most once every %v", description, shortDuration(s.throttle))
+	}
+
+	return description
+}
+
+// withLocation appends the location: loc (field) to the description (field).
+func (d describer) withLocation(description string, loc *time.Location) string {
+	if loc == nil {
+		return description
+	}
+
+	return d.tr("%v %v", description, loc)
+}
+
+// join joins the descriptions of schedules (field) with the phrase (field).
+func (d describer) join(phrase string, schedules []Schedule) string {
+	var description string
+	for i, sched := range schedules {
+		if i == 0 {
+			description = d.describe(sched)
+			continue
+		}
+
+		description = d.tr(phrase, description, d.describe(sched))
+	}
+
+	return description
+}
+
+func (d describer) between(s *betweenSchedule) string {
+	description := d.describe(s.schedule)
+	start, end := s.start.Format("2006-01-02 15:04:05 MST"), s.end.Format("2006-01-02 15:04:05 MST")
+
+	switch {
+	case !s.start.IsZero() && !s.end.IsZero():
+		return d.tr("%v, from %v until %v", description, start, end)
+	case !s.start.IsZero():
+		return d.tr("%v, from %v", description, start)
+	case !s.end.IsZero():
+		return d.tr("%v, until %v", description, end)
+	default:
+		return description
+	}
+}
+
+func (d describer) solar(s *solarSchedule) string {
+	event := d.tr(strings.ReplaceAll(s.event.String(), "-", " "))
+	coordinate := fmt.Sprintf(
+		"%v, %v",
+		strconv.FormatFloat(s.latitude, 'f', -1, 64),
+		strconv.FormatFloat(s.longitude, 'f', -1, 64),
+	)
+
+	switch {
+	case s.offset > 0:
+		return d.tr("%v after %v at %v", shortDuration(s.offset), event, coordinate)
+	case s.offset < 0:
+		return d.tr("%v before %v at %v", shortDuration(-s.offset), event, coordinate)
+	default:
+		return d.tr("at %v at %v", event, coordinate)
+	}
+}
+
+// spec describes the days and then the times of the spec schedule: s (field).
+func (d describer) spec(s *specSchedule) string {
+	if len(s.fields) != len(places) {
+		return s.String()
+	}
+
+	days := d.specDays(s)
+	times, ok := d.specTimes(s)
+
+	switch {
+	// a few times of day.
+	case ok:
+		return d.tr("%v %v", days, times)
+	case s.dom&starBit > 0 && s.dow&starBit > 0 && s.month&starBit > 0:
+		return times
+	default:
+		return d.tr("%v, %v", times, days)
+	}
+}
+
+// weekdayBits and weekendBits are the days of week of the weekdays and the weekend.
+const (
+	weekdayBits = 0b0111110
+	weekendBits = 0b1000001
+)
+
+// specDays describes the days of the spec schedule: s (field).
+func (d describer) specDays(s *specSchedule) string {
+	var dom, dow string
+	switch {
+	case s.dom&starBit > 0:
+	case s.lastDays == 1 && s.dom == 0 && s.nearestWeekdays == 0 && !s.lastWeekday:
+		dom = d.tr("on the last day of the month")
+	case s.lastDays > 0 || s.nearestWeekdays > 0 || s.lastWeekday:
+		dom = d.tr("on days %v of the month", s.fields[3])
+	default:
+		dom = d.monthDays(s.dom)
+	}
+
+	switch {
+	case s.dow&starBit > 0:
+	case s.lastDows > 0 || s.nthDows != [7]uint8{}:
+		dow = d.nthWeekdays(s)
+		if s.dow != 0 {
+			dow = d.tr("%v and %v", d.weekdays(s.dow), dow)
+		}
+	default:
+		dow = d.weekdays(s.dow)
+	}
+
+	var days string
+	switch {
+	case dom == "" && dow == "":
+		days = d.tr("every day")
+	case dom == "":
+		days = dow
+	case dow == "":
+		days = dom
+	default:
+		days = d.tr("%v and %v", dom, dow)
+	}
+
+	if months := s.month &^ starBit; s.month&starBit == 0 && months != bitRange(1, 12, 1) {
+		var names []string
+		for m := 1; m <= 12; m++ {
+			if months&(1<<uint(m)) > 0 {
+				names = append(names, d.tr(strings.ToLower(time.Month(m).String())))
+			}
+		}
+		days = d.tr("%v, in %v", days, d.list(names))
+	}
+
+	return days
+}
+
+// weekdays describes the days of week: dow (field).
+func (d describer) weekdays(dow uint64) string {
+	// sunday is both 0 and 7.
+	if dow&(1<<7) > 0 {
+		dow |= 1
+	}
+	dow &= 0x7f
+
+	switch dow {
+	case 0x7f:
+		return d.tr("every day")
+	case weekdayBits:
+		return d.tr("every weekday")
+	case weekendBits:
+		return d.tr("every weekend day")
+	}
+
+	var names []string
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if dow&(1<<uint(day)) > 0 {
+			names = append(names, d.tr(strings.ToLower(day.String())))
+		}
+	}
+
+	return d.tr("every %v", d.list(names))
+}
+
+// ordinals are the occurrences of a day of week in a month (n#k).
+var ordinals = []string{"", "first", "second", "third", "fourth", "fifth"}
+
+// nthWeekdays describes the last (nL) and k-th (n#k) days of week of the spec
+// schedule: s (field).
+func (d describer) nthWeekdays(s *specSchedule) string {
+	var days []string
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := d.tr(strings.ToLower(day.String()))
+
+		for k := 1; k < len(ordinals); k++ {
+			if s.nthDows[day]&(1<<uint(k)) > 0 {
+				days = append(days, d.tr("the %v %v", d.tr(ordinals[k]), name))
+			}
+		}
+
+		if s.lastDows&(1<<uint(day)) > 0 {
+			days = append(days, d.tr("the last %v", name))
+		}
+	}
+
+	return d.tr("on %v of the month", d.list(days))
+}
+
+// monthDays describes the days of month: dom (field).
+func (d describer) monthDays(dom uint64) string {
+	var days []string
+	for day := 1; day <= 31; day++ {
+		if dom&(1<<uint(day)) > 0 {
+			days = append(days, strconv.Itoa(day))
+		}
+	}
+
+	if len(days) == 1 {
+		return d.tr("on day %v of the month", days[0])
+	}
+	return d.tr("on days %v of the month", d.list(days))
+}
+
+// specTimes describes the times of day of the spec schedule: s (field).
+//
+// reports true if the description is a list of times of day.
+func (d describer) specTimes(s *specSchedule) (string, bool) {
+	seconds, minutes, hours := values(s.second), values(s.minute), values(s.hour)
+
+	// a few times of day.
+	if len(seconds) == 1 && len(minutes)*len(hours) <= 4 {
+		var times []string
+		for _, hour := range hours {
+			for _, minute := range minutes {
+				t := fmt.Sprintf("%02d:%02d", hour, minute)
+				if seconds[0] != 0 {
+					t += fmt.Sprintf(":%02d", seconds[0])
+				}
+				times = append(times, t)
+			}
+		}
+
+		return d.tr("at %v", d.list(times)), true
+	}
+
+	var description string
+	switch {
+	case s.fields[0] != "0" && len(minutes) == 60:
+		description = d.field(s.fields[0], seconds, "second")
+	case s.fields[0] != "0":
+		description = d.tr("%v %v", d.field(s.fields[0], seconds, "second"), d.field(s.fields[1], minutes, "minute"))
+	default:
+		description = d.field(s.fields[1], minutes, "minute")
+	}
+
+	switch {
+	case len(hours) == 24 && len(minutes) == 1:
+		return d.tr("%v %v", description, d.tr("of every hour")), false
+	case len(hours) == 24:
+		return description, false
+	case len(hours) == hours[len(hours)-1]-hours[0]+1:
+		// a range of hours.
+		return d.tr("%v, %v", description, d.tr("between %v and %v",
+			fmt.Sprintf("%02d:00", hours[0]),
+			fmt.Sprintf("%02d:59", hours[len(hours)-1]),
+		)), false
+	default:
+		return d.tr("%v, %v", description, d.tr("during hours %v", s.fields[2])), false
+	}
+}
+
+// field describes the seconds or minutes field: field (field), matching values (field).
+func (d describer) field(field string, values []int, unit string) string {
+	switch {
+	case field == "*":
+		return d.tr("every " + unit)
+	case len(values) == 1:
+		return d.tr("at "+unit+" %v", values[0])
+	}
+
+	if step := strings.TrimPrefix(field, "*/"); step != field {
+		return d.tr("every %v "+unit+"s", step)
+	}
+	return d.tr("at "+unit+"s %v", field)
+}
+
+// values returns the values set in the field: field (field).
+func values(field uint64) []int {
+	field &^= starBit
+
+	var values []int
+	for field > 0 {
+		n := bits.TrailingZeros64(field)
+		values = append(values, n)
+		field &^= 1 << uint(n)
+	}
+
+	return values
+}
+
+// shortDuration formats d (field) without its trailing zero units, 1h instead of 1h0m0s.
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+
+	return s
+}
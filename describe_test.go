@@ -0,0 +1,66 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	calendar := NewCalendar(time.Saturday, time.Sunday)
+
+	cases := []struct {
+		schedule Schedule
+		expected string
+	}{
+		{spec("CRON_TZ=Europe/Paris 0 9 * * 1-5")(), "every weekday at 09:00 Europe/Paris"},
+		{spec("*/15 9-17 * * *")(), "every 15 minutes, between 09:00 and 17:59"},
+		{spec("5 * * * *")(), "at minute 5 of every hour"},
+		{spec("0 0 L * *")(), "on the last day of the month at 00:00"},
+		{spec("30 8 * 1,7 sat,sun")(), "every weekend day, in january and july at 08:30"},
+		{spec("0 9,17 * * *")(), "every day at 09:00 and 17:00"},
+		{spec("0 0 1,15 * 1")(), "on days 1 and 15 of the month and every monday at 00:00"},
+		{spec("0 0 * * 5L,1#2")(), "on the second monday and the last friday of the month at 00:00"},
+		{spec("* * * * *")(), "every minute"},
+		{spec("@daily")(), "every day at 00:00"},
+		{Every(90 * time.Minute), "every 1h30m"},
+		{At(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)), "once at 2026-01-01 00:00:00 UTC"},
+		{Solar(Sunset, 52.52, 13.405, 30*time.Minute), "30m after sunset at 52.52, 13.405"},
+		{Union(spec("0 9 * * *")(), spec("0 17 * * *")()), "every day at 09:00 or every day at 17:00"},
+		{WithJitter(EveryFixed(time.Hour), 10*time.Minute), "every 1h, delayed by up to 10m"},
+		{RollForward(spec("0 9 1 * *")(), calendar), "on day 1 of the month at 09:00, moved to the next business day"},
+		{RollBackward(Every(time.Hour), calendar), "every 1h, moved to the previous business day"},
+		{OnChannel(make(chan int), WithDebounce(time.Second)), "when triggered, debounced by 1s"},
+		{OnChannel(make(chan int), WithThrottle(time.Minute)), "when triggered, at most once every 1m"},
+		{OnChannel(make(chan int), WithDebounce(time.Second), WithThrottle(time.Minute)), "when triggered, debounced by 1s, at most once every 1m"},
+		{ExponentialBackoff(time.Second, time.Minute, 2), "every 1s, backing off up to 1m while idle"},
+		{FibonacciBackoff(time.Second, time.Hour), "every 1s, backing off up to 1h while idle"},
+	}
+
+	for _, c := range cases {
+		if got := Describe(c.schedule, "en"); got != c.expected {
+			t.Fatalf("got: %q want: %q", got, c.expected)
+		}
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	RegisterLocale("fr", Locale{
+		"every weekday": "tous les jours de semaine",
+		"%v %v":         "%v %v",
+		"at %v":         "à %v",
+	})
+
+	sched := spec("0 9 * * 1-5")()
+	if got, want := Describe(sched, "fr"), "tous les jours de semaine à 09:00"; got != want {
+		t.Fatalf("got: %q want: %q", got, want)
+	}
+
+	// phrases missing from the locale and unknown locales are left in english.
+	sched = spec("0 9 * * 1")()
+	if got, want := Describe(sched, "fr"), "every monday à 09:00"; got != want {
+		t.Fatalf("got: %q want: %q", got, want)
+	}
+	if got, want := Describe(sched, "de"), "every monday at 09:00"; got != want {
+		t.Fatalf("got: %q want: %q", got, want)
+	}
+}
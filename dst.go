@@ -0,0 +1,118 @@
+package cronjob
+
+import (
+	"time"
+)
+
+// DSTPolicy determines how wall clock schedules behave when a daylight saving time
+// transition skips or repeats wall clock times.
+//
+// wall clock times repeated by a transition (clocks turned back) always run once, at
+// their first occurrence.
+type DSTPolicy int
+
+const (
+	// DSTAdjust runs wall clock times skipped by a transition (clocks turned forward)
+	// immediately after the skipped interval.
+	//
+	// example: with clocks turned forward at 02:00 to 03:00, a job scheduled at 02:30 runs
+	// at 03:00.
+	//
+	// this is the default policy.
+	DSTAdjust DSTPolicy = iota
+
+	// DSTSkip doesnt run wall clock times skipped by a transition (clocks turned forward).
+	//
+	// example: with clocks turned forward at 02:00 to 03:00, a job scheduled daily at 02:30
+	// doesnt run on the day of the transition.
+	DSTSkip
+)
+
+// WithDSTPolicy returns a copy of schedule (field) with the policy: policy (field).
+//
+// only schedules evaluated against wall clock readings (Parse, EveryFixed, ParseRRule,
+// ParseISO8601Interval, ParseSystemdCalendar and the schedules wrapping them) are
+// affected, other schedules are returned unchanged. schedule (field) is never changed,
+// it can be shared by other jobs and cronjobs.
+func WithDSTPolicy(schedule Schedule, policy DSTPolicy) Schedule {
+	sched, _ := withDSTPolicy(schedule, policy)
+	return sched
+}
+
+// wallClockSchedule is implemented by schedules evaluated against wall clock readings
+// and by the schedules wrapping them.
+type wallClockSchedule interface {
+	// withDSTPolicy returns a copy of the schedule with the policy, or the schedule
+	// itself and false if it already had it.
+	withDSTPolicy(DSTPolicy) (Schedule, bool)
+}
+
+// withDSTPolicy returns schedule (field) with the policy: policy (field), reports
+// whether it changed.
+func withDSTPolicy(schedule Schedule, policy DSTPolicy) (Schedule, bool) {
+	if sched, ok := schedule.(wallClockSchedule); ok {
+		return sched.withDSTPolicy(policy)
+	}
+
+	return schedule, false
+}
+
+// wallClock returns the instant at which the clock of loc (field) reads wall (field),
+// wall being a wall clock reading expressed in UTC.
+//
+// readings repeated by a transition resolve to their first occurrence, readings skipped
+// by a transition resolve to the end of the transition or, if policy (field) is DSTSkip,
+// report false.
+func wallClock(wall time.Time, loc *time.Location, policy DSTPolicy) (time.Time, bool) {
+	reading := wall.Unix()
+
+	// the offsets in use around the reading, a day is wider than any transition.
+	var offsets [3]int
+	for i, around := range []int64{reading - 86400, reading, reading + 86400} {
+		_, offsets[i] = time.Unix(around, 0).In(loc).Zone()
+	}
+
+	// the reading is valid for each offset which is in use at the instant it describes.
+	var (
+		first time.Time
+		found bool
+	)
+	for _, offset := range offsets {
+		t := time.Unix(reading-int64(offset), int64(wall.Nanosecond())).In(loc)
+		if _, actual := t.Zone(); actual != offset {
+			continue
+		}
+
+		if !found || t.Before(first) {
+			first, found = t, true
+		}
+	}
+
+	if found {
+		return first, true
+	}
+
+	// the reading was skipped.
+	if policy == DSTSkip {
+		return time.Time{}, false
+	}
+
+	return transitionAt(reading-int64(offsets[2]), reading-int64(offsets[0]), loc), true
+}
+
+// transitionAt returns the first instant between from (field) and to (field), unix seconds,
+// which uses the offset in use at to (field).
+func transitionAt(from, to int64, loc *time.Location) time.Time {
+	_, offset := time.Unix(to, 0).In(loc).Zone()
+
+	for from < to {
+		mid := from + (to-from)/2
+		if _, actual := time.Unix(mid, 0).In(loc).Zone(); actual == offset {
+			to = mid
+		} else {
+			from = mid + 1
+		}
+	}
+
+	return time.Unix(to, 0).In(loc)
+}
@@ -0,0 +1,104 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestDSTPolicy(t *testing.T) {
+	cases := []struct {
+		zone     string
+		schedule func() Schedule
+		policy   DSTPolicy
+		after    string
+		expected string
+	}{
+		// new york: 2022-03-13 02:00 EST -> 03:00 EDT.
+		{"America/New_York", spec("30 2 * * *"), DSTAdjust, "2022-03-13T05:00:00Z", "2022-03-13T07:00:00Z"},
+		{"America/New_York", spec("30 2 * * *"), DSTSkip, "2022-03-13T05:00:00Z", "2022-03-14T06:30:00Z"},
+		{"America/New_York", spec("0 3 * * *"), DSTAdjust, "2022-03-13T05:00:00Z", "2022-03-13T07:00:00Z"},
+
+		// new york: 2022-11-06 02:00 EDT -> 01:00 EST.
+		{"America/New_York", spec("30 1 * * *"), DSTAdjust, "2022-11-06T04:00:00Z", "2022-11-06T05:30:00Z"},
+		{"America/New_York", spec("30 1 * * *"), DSTAdjust, "2022-11-06T05:30:00Z", "2022-11-07T06:30:00Z"},
+		{"America/New_York", spec("30 2 * * *"), DSTAdjust, "2022-11-06T04:00:00Z", "2022-11-06T07:30:00Z"},
+		{"America/New_York", fixed(time.Hour), DSTAdjust, "2022-11-06T05:00:00Z", "2022-11-06T07:00:00Z"},
+		{"America/New_York", fixed(24 * time.Hour), DSTAdjust, "2022-03-13T05:00:00Z", "2022-03-14T04:00:00Z"},
+		{"America/New_York", fixed(24 * time.Hour), DSTAdjust, "2022-11-06T04:00:00Z", "2022-11-07T05:00:00Z"},
+
+		// berlin: 2022-03-27 02:00 CET -> 03:00 CEST.
+		{"Europe/Berlin", spec("30 2 * * *"), DSTAdjust, "2022-03-27T00:00:00Z", "2022-03-27T01:00:00Z"},
+		{"Europe/Berlin", spec("30 2 * * *"), DSTSkip, "2022-03-27T00:00:00Z", "2022-03-28T00:30:00Z"},
+		{"Europe/Berlin", spec("*/15 * * * *"), DSTAdjust, "2022-03-27T00:50:00Z", "2022-03-27T01:00:00Z"},
+		{"Europe/Berlin", spec("*/15 * * * *"), DSTAdjust, "2022-03-27T01:00:00Z", "2022-03-27T01:15:00Z"},
+
+		// berlin: 2022-10-30 03:00 CEST -> 02:00 CET.
+		{"Europe/Berlin", spec("30 2 * * *"), DSTAdjust, "2022-10-29T12:00:00Z", "2022-10-30T00:30:00Z"},
+		{"Europe/Berlin", spec("30 2 * * *"), DSTAdjust, "2022-10-30T00:30:00Z", "2022-10-31T01:30:00Z"},
+		{"Europe/Berlin", spec("*/15 * * * *"), DSTAdjust, "2022-10-30T00:45:00Z", "2022-10-30T02:00:00Z"},
+		{"Europe/Berlin", fixed(30 * time.Minute), DSTAdjust, "2022-10-30T00:30:00Z", "2022-10-30T02:00:00Z"},
+
+		// lord howe: 2022-10-02 02:00 +10:30 -> 02:30 +11.
+		{"Australia/Lord_Howe", spec("0 2 * * *"), DSTAdjust, "2022-10-01T12:00:00Z", "2022-10-01T15:30:00Z"},
+		{"Australia/Lord_Howe", spec("0 2 * * *"), DSTSkip, "2022-10-01T12:00:00Z", "2022-10-02T15:00:00Z"},
+		{"Australia/Lord_Howe", fixed(time.Hour), DSTAdjust, "2022-10-01T15:00:00Z", "2022-10-01T15:30:00Z"},
+		{"Australia/Lord_Howe", fixed(time.Hour), DSTSkip, "2022-10-01T15:00:00Z", "2022-10-01T16:00:00Z"},
+
+		// lord howe: 2022-04-03 02:00 +11 -> 01:30 +10:30.
+		{"Australia/Lord_Howe", spec("45 1 * * *"), DSTAdjust, "2022-04-02T14:00:00Z", "2022-04-02T14:45:00Z"},
+		{"Australia/Lord_Howe", spec("45 1 * * *"), DSTAdjust, "2022-04-02T14:45:00Z", "2022-04-03T15:15:00Z"},
+
+		// apia: 2011-12-30 was skipped entirely.
+		{"Pacific/Apia", spec("0 9 * * *"), DSTAdjust, "2011-12-29T22:00:00Z", "2011-12-30T10:00:00Z"},
+		{"Pacific/Apia", spec("0 9 * * *"), DSTSkip, "2011-12-29T22:00:00Z", "2011-12-30T19:00:00Z"},
+	}
+
+	for _, c := range cases {
+		loc, err := time.LoadLocation(c.zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sched := WithDSTPolicy(c.schedule(), c.policy).(CyclicSchedule)
+		after := mustParseTime(t, c.after).In(loc)
+		sched.MoveNextAvtivation(after)
+
+		if got, want := after.Add(sched.Calculate(after)), mustParseTime(t, c.expected); !got.Equal(want) {
+			t.Fatalf("%v after %v: got: %v want: %v", c.zone, c.after, got.UTC(), want)
+		}
+	}
+}
+
+func TestWithDSTPolicyWrapped(t *testing.T) {
+	sched, err := Parse("CRON_TZ=Europe/Berlin 30 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrapped := WithDSTPolicy(InLocation(sched, time.UTC), DSTSkip)
+	if got, want := wrapped.(*cyclicLocationSchedule).schedule.(*specSchedule).dst, DSTSkip; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// the schedule wrapped is shared, it keeps its policy.
+	if got, want := sched.(*specSchedule).dst, DSTAdjust; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func spec(spec string) func() Schedule {
+	return func() Schedule {
+		sched, err := Parse(spec)
+		if err != nil {
+			panic(err)
+		}
+		return sched
+	}
+}
+
+func fixed(every time.Duration) func() Schedule {
+	return func() Schedule {
+		return EveryFixed(every)
+	}
+}
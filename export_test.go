@@ -0,0 +1,41 @@
+package cronjob
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func init() {
+	RegisterJob("schedulertest", func(args ...string) error { return nil })
+}
+
+// NewLinkedList returns the default scheduler, for the tests of package cronjob_test.
+func NewLinkedList() Scheduler {
+	return &linkedList{}
+}
+
+// sqlSchedulers counts the schedulers returned by NewSQLScheduler, naming their databases.
+var sqlSchedulers int64
+
+// NewSQLScheduler returns the scheduler of a sql store with a new fake database, without
+// polling. the nodes added without a job are stored under a registered name.
+func NewSQLScheduler(t *testing.T) Scheduler {
+	name := fmt.Sprintf("%v-%v", t.Name(), atomic.AddInt64(&sqlSchedulers, 1))
+	store, _ := openFakeDB(t, name, WithPollInterval(0), WithLease(time.Minute))
+
+	return namedScheduler{store.Scheduler().(*sqlScheduler)}
+}
+
+// namedScheduler stores the nodes added without a job in the sql store.
+type namedScheduler struct {
+	*sqlScheduler
+}
+
+func (s namedScheduler) AddNode(now time.Time, node *Node) {
+	if node != nil && node.Job == nil {
+		node.Job = &Job{job: func() error { return nil }, name: "schedulertest"}
+	}
+	s.sqlScheduler.AddNode(now, node)
+}
@@ -0,0 +1,261 @@
+package cronjob
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// compactAfter is the number of entries of a file store replaced or deleted after which
+// the file is compacted, once they outnumber the jobs.
+const compactAfter = 1000
+
+// NewFileStore opens the store kept in the file at path (field), creating it if it
+// doesnt exist.
+//
+// the store appends an entry to the file for each job saved or deleted, the file is
+// compacted to an entry per job when opened and once the replaced and deleted entries
+// outnumber the jobs. an entry left incomplete by a crash is dropped.
+//
+// example:
+//
+//	store, err := cronjob.NewFileStore("/var/lib/app/jobs.log")
+//	if err != nil {
+//		// handle error.
+//	}
+//	defer store.Close()
+//
+//	c := cronjob.New(cronjob.WithStore(store))
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path: path,
+		jobs: make(map[int]StoredJob),
+	}
+
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// FileStore implements Store with an append-only file, see NewFileStore.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+
+	// jobs holds the jobs of the file.
+	jobs map[int]StoredJob
+
+	// entries is the number of entries in the file.
+	entries int
+}
+
+// fileEntry is an entry of the file of a file store, saving the job: Job (field) or
+// deleting the job with id: Delete (field).
+type fileEntry struct {
+	Job    *StoredJob `json:"job,omitempty"`
+	Delete int        `json:"delete,omitempty"`
+}
+
+// Load returns all the jobs in the store, ordered by id.
+func (s *FileStore) Load() ([]StoredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]StoredJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Id < jobs[j].Id
+	})
+	return jobs, nil
+}
+
+func (s *FileStore) Save(job StoredJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.append(fileEntry{Job: &job}); err != nil {
+		return err
+	}
+
+	s.jobs[job.Id] = job
+	return s.maybeCompact()
+}
+
+func (s *FileStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[id]; !ok {
+		return nil
+	}
+
+	if err := s.append(fileEntry{Delete: id}); err != nil {
+		return err
+	}
+
+	delete(s.jobs, id)
+	return s.maybeCompact()
+}
+
+// Compact re-writes the file with an entry per job.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("cronjob: %q: store closed", s.path)
+	}
+	return s.compact()
+}
+
+// Close closes the file of the store.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// replay reads the jobs of the file.
+//
+// no-op if the file doesnt exist.
+func (s *FileStore) replay() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cronjob: %q: %w", s.path, err)
+	}
+
+	r := bufio.NewReader(bytes.NewReader(data))
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// the last entry wasnt written fully.
+			return nil
+		}
+
+		var entry fileEntry
+		if err := json.Unmarshal(b, &entry); err != nil {
+			return fmt.Errorf("cronjob: %q: line %v: %w", s.path, line, err)
+		}
+
+		if entry.Job != nil {
+			s.jobs[entry.Job.Id] = *entry.Job
+		} else {
+			delete(s.jobs, entry.Delete)
+		}
+		s.entries++
+	}
+}
+
+// append writes the entry (field) at the end of the file and syncs it.
+func (s *FileStore) append(entry fileEntry) error {
+	if s.file == nil {
+		return fmt.Errorf("cronjob: %q: store closed", s.path)
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("cronjob: %q: %w", s.path, err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("cronjob: %q: %w", s.path, err)
+	}
+
+	s.entries++
+	return nil
+}
+
+// maybeCompact compacts the file once the replaced and deleted entries outnumber the
+// jobs, and compactAfter.
+func (s *FileStore) maybeCompact() error {
+	if stale := s.entries - len(s.jobs); stale < compactAfter || stale < len(s.jobs) {
+		return nil
+	}
+
+	return s.compact()
+}
+
+// compact writes the jobs to a new file replacing the file of the store, and opens it.
+func (s *FileStore) compact() error {
+	ids := make([]int, 0, len(s.jobs))
+	for id := range s.jobs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var buf bytes.Buffer
+	for _, id := range ids {
+		job := s.jobs[id]
+
+		b, err := json.Marshal(fileEntry{Job: &job})
+		if err != nil {
+			return err
+		}
+		buf.Write(append(b, '\n'))
+	}
+
+	// write to a temporary file first, the file is replaced at once.
+	tmp := s.path + ".tmp"
+	if err := writeFileSync(tmp, buf.Bytes()); err != nil {
+		return fmt.Errorf("cronjob: %q: %w", s.path, err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("cronjob: %q: %w", s.path, err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("cronjob: %q: %w", s.path, err)
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = file
+	s.entries = len(ids)
+	return nil
+}
+
+// writeFileSync writes data (field) to the file at path (field), flushed to the disk.
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,162 @@
+package cronjob
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// describeJobs returns the jobs (field) as text.
+func describeJobs(jobs []StoredJob) []string {
+	var lines []string
+	for _, job := range jobs {
+		lines = append(lines, job.Name+" "+scheduleText(job.Schedule.Schedule)+" "+job.NextRun.UTC().Format(time.RFC3339))
+	}
+	return lines
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.log")
+	at := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, job := range []StoredJob{
+		{Id: 1, Name: "a", Schedule: ScheduleValue{At(at)}, NextRun: at},
+		{Id: 2, Name: "b", Args: []string{"x"}, Schedule: ScheduleValue{EveryFixed(time.Hour)}},
+		{Id: 3, Name: "c", Schedule: ScheduleValue{EveryFixed(time.Minute)}},
+		{Id: 2, Name: "b", Args: []string{"x"}, Schedule: ScheduleValue{EveryFixed(time.Hour)}, NextRun: at},
+	} {
+		if err := store.Save(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(42); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"a @at 2030-01-01T09:00:00Z 2030-01-01T09:00:00Z",
+		"b @every-fixed 1h0m0s 2030-01-01T09:00:00Z",
+	}
+
+	jobs, _ := store.Load()
+	if got := describeJobs(jobs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	store.Close()
+
+	if err := store.Save(StoredJob{Id: 4}); err == nil {
+		t.Fatal("got: nil want: error")
+	}
+
+	// a crash left an entry incomplete.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"job":{"id":5,"na`)
+	f.Close()
+
+	store, err = NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	jobs, _ = store.Load()
+	if got := describeJobs(jobs); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := jobs[1].Args, []string{"x"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestFileStoreCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.log")
+	if err := os.WriteFile(path, []byte("{\"delete\":1}\nnot json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewFileStore(path); err == nil {
+		t.Fatal("got: nil want: error")
+	}
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.log")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// the job is replaced each time it runs.
+	next := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	for i := 0; i <= compactAfter; i++ {
+		next = next.Add(time.Minute)
+		if err := store.Save(StoredJob{Id: 1, Name: "a", Schedule: ScheduleValue{EveryFixed(time.Minute)}, NextRun: next}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := bytes.Count(data, []byte("\n")), 1; got != want {
+		t.Fatalf("got: %v entries want: %v", got, want)
+	}
+
+	jobs, _ := store.Load()
+	if got, want := jobs[0].NextRun, next; !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestFileStoreRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.log")
+	at := time.Now().Add(time.Hour)
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(WithStore(store))
+	c.Start()
+	id, err := c.AddNamedFunc("store-test", At(at), "restart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Jobs()
+	c.Stop()
+	store.Close()
+
+	// the job is added back by the next process.
+	store, err = NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	c = New(WithStore(store))
+	c.Start()
+	defer c.Stop()
+
+	nodes := c.Jobs()
+	if len(nodes) != 1 || nodes[0].Id != id || !nodes[0].NextRun.Equal(at) {
+		t.Fatalf("got: %v want: job %v at %v", nodes, id, at)
+	}
+}
@@ -0,0 +1,173 @@
+package cronjob
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// NewHeapScheduler returns a scheduler keeping the nodes in a min-heap ordered by their
+// next activation, with an index of the nodes by id.
+//
+// adding, removing and re-calculating a node takes O(log n), use it with WithScheduler
+// for cronjobs with many jobs:
+//
+//	cronjob.New(cronjob.WithScheduler(cronjob.NewHeapScheduler()))
+//
+// the ids of the nodes must be unique, a node added with the id of another replaces it.
+func NewHeapScheduler() Scheduler {
+	return &heapScheduler{
+		index: make(map[int]int),
+	}
+}
+
+// heapScheduler implements Scheduler and heap.Interface.
+type heapScheduler struct {
+	nodes []*Node
+
+	// index maps the ids of the nodes to their position in nodes (field).
+	index map[int]int
+}
+
+// NextCycle gets the duration of sleeping before activating.
+func (h *heapScheduler) NextCycle(now time.Time) time.Duration {
+	if len(h.nodes) == 0 {
+		return -1
+	}
+
+	// the root node has the earliest activation.
+	if v := h.nodes[0].NextRun.Sub(now); v > 0 {
+		return v
+	}
+	return 0
+}
+
+// RunNow gets all the nodes scheduled to run now or in the past, in the order of their
+// activations.
+func (h *heapScheduler) RunNow(now time.Time) (nodes []*Node) {
+	// the children of a node which doesnt run dont run either.
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if i >= len(h.nodes) || h.nodes[i].NextRun.After(now) {
+			continue
+		}
+
+		nodes = append(nodes, h.nodes[i])
+		stack = append(stack, 2*i+1, 2*i+2)
+	}
+
+	sortNodes(nodes)
+	return
+}
+
+// GetAll returns all the nodes in the order of their activations.
+func (h *heapScheduler) GetAll() []*Node {
+	nodes := make([]*Node, len(h.nodes))
+	copy(nodes, h.nodes)
+
+	sortNodes(nodes)
+	return nodes
+}
+
+// AddNode adds the node (field) in the heap based on its next activation.
+//
+// no-op if node is nil or its schedule has no further activations.
+func (h *heapScheduler) AddNode(now time.Time, node *Node) {
+	if node == nil {
+		return
+	}
+
+	node.NextRun = nextRun(now, node.Schedule)
+	if node.NextRun.IsZero() {
+		return
+	}
+
+	h.RemoveNode(node.Id)
+	heap.Push(h, node)
+}
+
+// RemoveNode removes the node with the given id.
+//
+// no-op if node not found.
+func (h *heapScheduler) RemoveNode(id int) {
+	if i, ok := h.index[id]; ok {
+		heap.Remove(h, i)
+	}
+}
+
+// Clean removes the nodes (field) and re-calculates the cyclic ones.
+func (h *heapScheduler) Clean(now time.Time, nodes []*Node) {
+	for _, node := range nodes {
+		i, ok := h.index[node.Id]
+		if !ok {
+			continue
+		}
+
+		if _, cyclic := node.Schedule.(CyclicSchedule); !cyclic {
+			heap.Remove(h, i)
+			continue
+		}
+
+		next := rescheduled(now, node)
+		if next.IsZero() {
+			heap.Remove(h, i)
+			continue
+		}
+
+		node.NextRun = next
+		heap.Fix(h, i)
+	}
+}
+
+// node returns the node with the given id, nil if not found.
+func (h *heapScheduler) node(id int) *Node {
+	if i, ok := h.index[id]; ok {
+		return h.nodes[i]
+	}
+
+	return nil
+}
+
+func (h *heapScheduler) Len() int {
+	return len(h.nodes)
+}
+
+func (h *heapScheduler) Less(i, j int) bool {
+	return h.nodes[i].NextRun.Before(h.nodes[j].NextRun)
+}
+
+func (h *heapScheduler) Swap(i, j int) {
+	h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i]
+	h.index[h.nodes[i].Id] = i
+	h.index[h.nodes[j].Id] = j
+}
+
+func (h *heapScheduler) Push(x interface{}) {
+	node := x.(*Node)
+
+	h.index[node.Id] = len(h.nodes)
+	h.nodes = append(h.nodes, node)
+}
+
+func (h *heapScheduler) Pop() interface{} {
+	last := len(h.nodes) - 1
+	node := h.nodes[last]
+
+	h.nodes[last] = nil
+	h.nodes = h.nodes[:last]
+	delete(h.index, node.Id)
+	return node
+}
+
+// sortNodes sorts the nodes (field) by their next activation, then by id.
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if !nodes[i].NextRun.Equal(nodes[j].NextRun) {
+			return nodes[i].NextRun.Before(nodes[j].NextRun)
+		}
+		return nodes[i].Id < nodes[j].Id
+	})
+}
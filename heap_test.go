@@ -0,0 +1,172 @@
+package cronjob
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestHeapScheduler(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	n1 := &Node{Id: 1, Schedule: In(now, 5*time.Second)}
+	n2 := &Node{Id: 2, Schedule: Every(2 * time.Second)}
+	n3 := &Node{Id: 3, Schedule: In(now, 3*time.Second)}
+
+	h := NewHeapScheduler()
+	if got, want := h.NextCycle(now), time.Duration(-1); got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	for _, node := range []*Node{n1, n2, n3} {
+		h.AddNode(now, node)
+	}
+
+	if got, want := h.GetAll(), []*Node{n2, n3, n1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := h.NextCycle(now), 2*time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// the cyclic node is re-calculated, the constant one removed.
+	nodes := h.RunNow(now.Add(3 * time.Second))
+	if got, want := nodes, []*Node{n2, n3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	h.Clean(now.Add(3*time.Second), nodes)
+	if got, want := h.GetAll(), []*Node{n2, n1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := n2.NextRun, now.Add(4*time.Second); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	h.RemoveNode(n2.Id)
+	h.RemoveNode(42)
+	if got, want := h.GetAll(), []*Node{n1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// a node with the id of another replaces it, exhausted schedules arent added.
+	n4 := &Node{Id: 1, Schedule: In(now, time.Second)}
+	h.AddNode(now, n4)
+
+	sched, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.AddNode(now, &Node{Id: 5, Schedule: sched})
+
+	if got, want := h.GetAll(), []*Node{n4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestHeapSchedulerMatchesLinkedList(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	r := rand.New(rand.NewSource(1))
+
+	h, l := NewHeapScheduler(), &linkedList{}
+	for id := 1; id <= 200; id++ {
+		every := time.Duration(1+r.Intn(60)) * time.Second
+		h.AddNode(now, &Node{Id: id, Schedule: EveryFixed(every)})
+		l.AddNode(now, &Node{Id: id, Schedule: EveryFixed(every)})
+	}
+
+	for i := 0; i < 100; i++ {
+		if got, want := h.NextCycle(now), l.NextCycle(now); got != want {
+			t.Fatalf("cycle %v: got: %v want: %v", i, got, want)
+		}
+		now = now.Add(h.NextCycle(now))
+
+		got, want := h.RunNow(now), l.RunNow(now)
+		if ids(got) != ids(want) {
+			t.Fatalf("cycle %v: got: %v want: %v", i, ids(got), ids(want))
+		}
+
+		h.Clean(now, got)
+		l.Clean(now, want)
+
+		// remove a node now and then.
+		if i%10 == 0 {
+			id := 1 + r.Intn(200)
+			h.RemoveNode(id)
+			l.RemoveNode(id)
+		}
+	}
+}
+
+// ids returns the ids of the nodes (field), sorted.
+func ids(nodes []*Node) string {
+	sorted := make([]int, 0, len(nodes))
+	for _, node := range nodes {
+		sorted = append(sorted, node.Id)
+	}
+
+	sort.Ints(sorted)
+	return fmt.Sprint(sorted)
+}
+
+// schedulers are the schedulers compared by the benchmarks.
+var schedulers = []struct {
+	name         string
+	newScheduler func() Scheduler
+}{
+	{"linkedList", func() Scheduler { return &linkedList{} }},
+	{"heap", NewHeapScheduler},
+	{"wheel", func() Scheduler { return NewWheelScheduler(time.Second) }},
+}
+
+// filled returns a new scheduler with n (field) nodes.
+func filled(newScheduler func() Scheduler, now time.Time, n int) Scheduler {
+	s := newScheduler()
+	r := rand.New(rand.NewSource(1))
+
+	for id := 1; id <= n; id++ {
+		s.AddNode(now, &Node{Id: id, Schedule: EveryFixed(time.Duration(1+r.Intn(3600)) * time.Second)})
+	}
+	return s
+}
+
+func BenchmarkAddRemoveNode(b *testing.B) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	for _, n := range []int{1000, 10000} {
+		for _, sched := range schedulers {
+			b.Run(fmt.Sprintf("%v/%v", sched.name, n), func(b *testing.B) {
+				s := filled(sched.newScheduler, now, n)
+				node := &Node{Id: n + 1, Schedule: EveryFixed(30 * time.Minute)}
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					s.AddNode(now, node)
+					s.RemoveNode(node.Id)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCycle(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		for _, sched := range schedulers {
+			b.Run(fmt.Sprintf("%v/%v", sched.name, n), func(b *testing.B) {
+				now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+				s := filled(sched.newScheduler, now, n)
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					now = now.Add(s.NextCycle(now))
+
+					nodes := s.RunNow(now)
+					s.Clean(now, nodes)
+				}
+			})
+		}
+	}
+}
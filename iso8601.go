@@ -0,0 +1,355 @@
+package cronjob
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isoDurationRegexp matches the ISO 8601 durations, PnYnMnWnDTnHnMnS.
+var isoDurationRegexp = regexp.MustCompile(
+	`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`,
+)
+
+// ParseISO8601Interval parses an ISO 8601 repeating interval and returns a schedule which
+// runs at the start of each repetition.
+//
+// the interval can be described by its start and duration, start and end or duration and
+// end:
+//
+//	R5/2026-01-01T00:00:00Z/PT15M
+//	R5/2026-01-01T00:00:00Z/2026-01-01T00:15:00Z
+//	R5/PT15M/2026-01-01T01:15:00Z
+//
+// all of them run 5 times, every 15 minutes from 2026-01-01 00:00 UTC. the number of
+// repetitions can be omitted (R/...) to repeat forever.
+//
+// years, months, weeks and days of a duration are added to the wall clock reading, so
+// P1M runs on the same day of each month (or the last day of shorter months) and P1D
+// at the same time of each day. durations longer than a time.Duration (about 292 years)
+// are rejected.
+//
+// times without an offset are evaluated in the location used by the cronjob.
+func ParseISO8601Interval(interval string) (Schedule, error) {
+	parts := strings.Split(interval, "/")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "R") {
+		return nil, fmt.Errorf("cronjob: expected R[n]/start/duration, R[n]/start/end or R[n]/duration/end: %q", interval)
+	}
+
+	sched := &isoSchedule{
+		source: interval,
+		first:  0,
+		last:   math.MaxInt64,
+	}
+
+	if count := parts[0][1:]; count != "" {
+		n, err := strconv.ParseInt(count, 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("cronjob: %q: repetitions should be a non negative number", interval)
+		}
+		sched.last = n - 1
+	}
+
+	var err error
+	switch {
+	// duration and end, the repetitions end at the end.
+	case strings.HasPrefix(parts[1], "P"):
+		if sched.duration, err = parseISODuration(parts[1]); err != nil {
+			break
+		}
+
+		if sched.anchor, sched.location, err = parseISOTime(parts[2]); err != nil {
+			break
+		}
+
+		if sched.last == math.MaxInt64 {
+			sched.first = math.MinInt64
+		} else {
+			sched.first = -sched.last - 1
+		}
+		sched.last = -1
+
+	// start and duration.
+	case strings.HasPrefix(parts[2], "P"):
+		if sched.anchor, sched.location, err = parseISOTime(parts[1]); err != nil {
+			break
+		}
+
+		sched.duration, err = parseISODuration(parts[2])
+
+	// start and end.
+	default:
+		if sched.anchor, sched.location, err = parseISOTime(parts[1]); err != nil {
+			break
+		}
+
+		var (
+			end    time.Time
+			endLoc *time.Location
+		)
+		if end, endLoc, err = parseISOTime(parts[2]); err != nil {
+			break
+		}
+
+		// compare the instants if both have an offset, the wall clock readings otherwise.
+		start := sched.anchor
+		if sched.location != nil && endLoc != nil {
+			start, _ = wallClock(start, sched.location, DSTAdjust)
+			end, _ = wallClock(end, endLoc, DSTAdjust)
+		}
+
+		sched.duration.clock = end.Sub(start)
+		if sched.duration.clock < 0 {
+			err = fmt.Errorf("end before start")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cronjob: %q: %w", interval, err)
+	}
+
+	if sched.duration.isZero() && sched.first != sched.last {
+		return nil, fmt.Errorf("cronjob: %q: zero duration can only be repeated once", interval)
+	}
+
+	return sched, nil
+}
+
+// isoDuration is an ISO 8601 duration, split in its calendar and clock components.
+type isoDuration struct {
+	years, months, days int64
+
+	clock time.Duration
+}
+
+func (d isoDuration) isZero() bool {
+	return d.years == 0 && d.months == 0 && d.days == 0 && d.clock == 0
+}
+
+// approximate returns the approximate length of the duration.
+func (d isoDuration) approximate() time.Duration {
+	return time.Duration(d.years)*time.Duration(365.2425*float64(day)) +
+		time.Duration(d.months)*time.Duration(30.436875*float64(day)) +
+		time.Duration(d.days)*day +
+		d.clock
+}
+
+// IsoSchedule ------------------------------------------------------------------
+
+type isoSchedule struct {
+	// anchor is the wall clock reading, expressed in UTC, which the repetitions are
+	// multiples of duration away from.
+	anchor time.Time
+
+	// location is the location of anchor, if nil the location of the time passed to the
+	// schedule is used.
+	location *time.Location
+
+	duration isoDuration
+
+	// source is the interval the schedule was parsed from.
+	source string
+
+	// first and last are the multiples of duration of the first and last repetitions.
+	first, last int64
+
+	// dst is the policy used for wall clock times skipped by daylight saving time.
+	dst DSTPolicy
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *isoSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *isoSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *isoSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	if s.dst == policy {
+		return s, false
+	}
+
+	set := *s
+	set.dst = policy
+	return &set, true
+}
+
+// String returns the @iso8601 descriptor of the interval.
+func (s *isoSchedule) String() string {
+	return withDST(s.dst, "@iso8601 "+s.source)
+}
+
+func (s *isoSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *isoSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *isoSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first repetition after: after (field).
+//
+// returns the zero time if there are no further repetitions.
+func (s *isoSchedule) Next(after time.Time) time.Time {
+	if s.first > s.last {
+		return time.Time{}
+	}
+
+	loc := after.Location()
+	if s.location != nil {
+		loc = s.location
+	}
+
+	// estimate the repetition before after (field), then walk to the first one after it.
+	var k int64
+	if !s.duration.isZero() {
+		k = int64(wallReading(after.In(loc)).Sub(s.anchor) / s.duration.approximate())
+	}
+	k = clampInt64(k, s.first, s.last)
+
+	for ; k > s.first; k-- {
+		if t, ok := s.repetition(k, loc); ok && !t.After(after) {
+			break
+		}
+	}
+	for ; k <= s.last; k++ {
+		if t, ok := s.repetition(k, loc); ok && t.After(after) {
+			return t
+		}
+
+		if k == math.MaxInt64 {
+			break
+		}
+	}
+
+	return time.Time{}
+}
+
+// repetition returns the time of the repetition k (field) multiples of the duration away
+// from the anchor.
+//
+// reports false if the wall clock reading of the repetition was skipped by daylight
+// saving time and the policy is DSTSkip.
+func (s *isoSchedule) repetition(k int64, loc *time.Location) (time.Time, bool) {
+	// the calendar components are added to the wall clock reading, the clock component to
+	// the instant.
+	wall := addMonths(s.anchor, k*(s.duration.years*12+s.duration.months)).AddDate(0, 0, int(k*s.duration.days))
+
+	t, ok := wallClock(wall, loc, s.dst)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return t.Add(time.Duration(k) * s.duration.clock), true
+}
+
+// addMonths adds n (field) months to t (field), clamping the day to the last day of the
+// resulting month.
+func addMonths(t time.Time, n int64) time.Time {
+	if n == 0 {
+		return t
+	}
+
+	months := int64(t.Year())*12 + int64(t.Month()-1) + n
+	year, month := int(floorDiv(months, 12)), time.Month(months-floorDiv(months, 12)*12+1)
+
+	d := t.Day()
+	if last := daysIn(year, month); d > last {
+		d = last
+	}
+
+	return time.Date(year, month, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// parseISOTime parses an ISO 8601 date-time, returning its wall clock reading expressed in
+// UTC and its location, nil if it has no offset.
+func parseISOTime(value string) (time.Time, *time.Location, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return wallReading(t), t.Location(), nil
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04:05.999999999", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil, nil
+		}
+	}
+
+	return time.Time{}, nil, fmt.Errorf("invalid date-time: %q", value)
+}
+
+// parseISODuration parses an ISO 8601 duration, only seconds can have a fraction.
+func parseISODuration(value string) (isoDuration, error) {
+	match := isoDurationRegexp.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return isoDuration{}, fmt.Errorf("invalid duration: %q", value)
+	}
+
+	// the components, years, months, weeks, days, hours and minutes.
+	var n [6]int64
+	for i := range n {
+		if match[i+1] == "" {
+			continue
+		}
+
+		v, err := strconv.ParseInt(match[i+1], 10, 64)
+		if err != nil {
+			return isoDuration{}, fmt.Errorf("invalid duration: %q: %w", value, err)
+		}
+		n[i] = v
+	}
+
+	var seconds float64
+	if match[7] != "" {
+		v, err := strconv.ParseFloat(strings.Replace(match[7], ",", ".", 1), 64)
+		if err != nil {
+			return isoDuration{}, fmt.Errorf("invalid duration: %q: %w", value, err)
+		}
+		seconds = v
+	}
+
+	// the components are multiplied below, they are bounded by the duration which has to
+	// fit in a time.Duration.
+	length := float64(n[0])*365.2425*float64(day) +
+		float64(n[1])*30.436875*float64(day) +
+		(float64(n[2])*7+float64(n[3]))*float64(day) +
+		float64(n[4])*float64(time.Hour) +
+		float64(n[5])*float64(time.Minute) +
+		seconds*float64(time.Second)
+	if length >= math.MaxInt64 {
+		return isoDuration{}, fmt.Errorf("duration too long: %q", value)
+	}
+
+	return isoDuration{
+		years:  n[0],
+		months: n[1],
+		days:   n[2]*7 + n[3],
+		clock: time.Duration(n[4])*time.Hour +
+			time.Duration(n[5])*time.Minute +
+			time.Duration(seconds*float64(time.Second)),
+	}, nil
+}
+
+func clampInt64(v, min, max int64) int64 {
+	switch {
+	case v < min:
+		return min
+	case v > max:
+		return max
+	}
+
+	return v
+}
@@ -0,0 +1,172 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestISO8601Interval(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		interval string
+		after    time.Time
+		expected []string
+	}{
+		// start and duration, start and end and duration and end describe the same interval, the
+		// zero time marks the end of the repetitions.
+		{
+			interval: "R5/2026-01-01T00:00:00Z/PT15M",
+			after:    time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			expected: []string{
+				"2026-01-01T00:00:00Z", "2026-01-01T00:15:00Z", "2026-01-01T00:30:00Z",
+				"2026-01-01T00:45:00Z", "2026-01-01T01:00:00Z", "",
+			},
+		},
+		{
+			interval: "R5/2026-01-01T00:00:00Z/2026-01-01T00:15:00Z",
+			after:    time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			expected: []string{
+				"2026-01-01T00:00:00Z", "2026-01-01T00:15:00Z", "2026-01-01T00:30:00Z",
+				"2026-01-01T00:45:00Z", "2026-01-01T01:00:00Z", "",
+			},
+		},
+		{
+			interval: "R5/PT15M/2026-01-01T01:15:00Z",
+			after:    time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			expected: []string{
+				"2026-01-01T00:00:00Z", "2026-01-01T00:15:00Z", "2026-01-01T00:30:00Z",
+				"2026-01-01T00:45:00Z", "2026-01-01T01:00:00Z", "",
+			},
+		},
+		{
+			interval: "R2/2026-01-01T09:00:00+01:00/2026-01-01T09:00:00Z",
+			after:    time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			expected: []string{"2026-01-01T08:00:00Z", "2026-01-01T09:00:00Z", ""},
+		},
+
+		// skips ahead to the repetition after: after (field).
+		{
+			interval: "R/2020-01-01T00:00:00Z/PT7M",
+			after:    time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC),
+			expected: []string{"2026-10-17T10:01:00Z", "2026-10-17T10:08:00Z"},
+		},
+		{
+			interval: "R/P1D/2026-01-10T00:00:00Z",
+			after:    time.Date(2026, 1, 7, 12, 0, 0, 0, time.UTC),
+			expected: []string{"2026-01-08T00:00:00Z", "2026-01-09T00:00:00Z", ""},
+		},
+
+		// months run on the same day, or the last day of shorter months.
+		{
+			interval: "R4/2026-01-31T09:00:00Z/P1M",
+			after:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []string{
+				"2026-01-31T09:00:00Z", "2026-02-28T09:00:00Z", "2026-03-31T09:00:00Z",
+				"2026-04-30T09:00:00Z", "",
+			},
+		},
+		{
+			interval: "R3/2024-02-29T00:00:00Z/P1Y",
+			after:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []string{"2024-02-29T00:00:00Z", "2025-02-28T00:00:00Z", "2026-02-28T00:00:00Z", ""},
+		},
+
+		// times without an offset are evaluated in the location of after (field), days keep
+		// the wall clock time across daylight saving time, hours dont.
+		{
+			interval: "R3/2026-03-07T09:00:00/P1D",
+			after:    time.Date(2026, 3, 1, 0, 0, 0, 0, newYork),
+			expected: []string{
+				"2026-03-07T09:00:00-05:00", "2026-03-08T09:00:00-04:00", "2026-03-09T09:00:00-04:00", "",
+			},
+		},
+		{
+			interval: "R2/2026-03-07T09:00:00/PT24H",
+			after:    time.Date(2026, 3, 1, 0, 0, 0, 0, newYork),
+			expected: []string{"2026-03-07T09:00:00-05:00", "2026-03-08T10:00:00-04:00", ""},
+		},
+		{
+			interval: "R2/2026-03-07/P1W",
+			after:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: []string{"2026-03-07T00:00:00Z", "2026-03-14T00:00:00Z", ""},
+		},
+
+		// a single repetition can have a zero duration.
+		{
+			interval: "R1/2026-01-01T00:00:00Z/PT0S",
+			after:    time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			expected: []string{"2026-01-01T00:00:00Z", ""},
+		},
+		{
+			interval: "R0/2026-01-01T00:00:00Z/PT15M",
+			after:    time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			expected: []string{""},
+		},
+	}
+
+	for _, c := range cases {
+		sched, err := ParseISO8601Interval(c.interval)
+		if err != nil {
+			t.Fatalf("%q: %v", c.interval, err)
+		}
+
+		after := c.after
+		for i, expected := range c.expected {
+			got := sched.(*isoSchedule).Next(after)
+			if want := mustParseTime(t, expected); !got.Equal(want) {
+				t.Fatalf("%q: repetition %v: got: %v want: %v", c.interval, i, got, want)
+			}
+			after = got
+		}
+	}
+}
+
+func TestISO8601IntervalDSTSkip(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sched, err := ParseISO8601Interval("R3/2026-03-07T02:30:00/P1D")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sched = WithDSTPolicy(sched, DSTSkip)
+
+	// 02:30 is skipped on 2026-03-08.
+	got := sched.(*isoSchedule).Next(time.Date(2026, 3, 7, 12, 0, 0, 0, newYork))
+	if want := time.Date(2026, 3, 9, 2, 30, 0, 0, newYork); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestParseISO8601IntervalErrors(t *testing.T) {
+	for _, interval := range []string{
+		"",
+		"R5",
+		"R5/2026-01-01T00:00:00Z",
+		"5/2026-01-01T00:00:00Z/PT15M",
+		"Rx/2026-01-01T00:00:00Z/PT15M",
+		"R-1/2026-01-01T00:00:00Z/PT15M",
+		"R5/2026-13-01T00:00:00Z/PT15M",
+		"R5/2026-01-01T00:00:00Z/P",
+		"R5/2026-01-01T00:00:00Z/PT",
+		"R5/2026-01-01T00:00:00Z/P1.5D",
+		"R5/2026-01-01T00:00:00Z/PT0S",
+		"R5/2026-01-01T00:15:00Z/2026-01-01T00:00:00Z",
+		"R5/PT15M/PT15M",
+		"R5/2026-01-01T00:00:00Z/PT15M/PT15M",
+		"R5/2026-01-01T00:00:00Z/PT99999999999999999999H",
+		"R5/2026-01-01T00:00:00Z/PT9223372036854775807H",
+		"R5/2026-01-01T00:00:00Z/P300Y",
+		"R5/2026-01-01T00:00:00Z/P1999999999999999999W",
+	} {
+		if _, err := ParseISO8601Interval(interval); err == nil {
+			t.Fatalf("%q: expected error", interval)
+		}
+	}
+}
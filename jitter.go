@@ -0,0 +1,191 @@
+package cronjob
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// WithJitter returns a schedule which delays each activation of schedule (field) by a
+// random offset between 0 and max (field).
+//
+// example:
+//
+//	cronjob.WithJitter(cronjob.EveryFixed(time.Hour), time.Minute * 10)
+//
+// the schedule runs each hour, at a random time in the first 10 minutes.
+//
+// max (field) should be shorter than the time between the activations, activations
+// delayed past the next one run in the order of the schedule.
+func WithJitter(schedule Schedule, max time.Duration) Schedule {
+	return &jitterSchedule{
+		schedule: schedule,
+		max:      max,
+		random:   true,
+		seed:     rand.New(rand.NewSource(time.Now().UnixNano())).Uint64(),
+	}
+}
+
+// WithStableJitter returns a schedule which delays each activation of schedule (field)
+// by an offset between 0 and max (field) derived from key (field).
+//
+// example:
+//
+//	cronjob.WithStableJitter(cronjob.EveryFixed(time.Hour), time.Minute * 10, "billing-report")
+//
+// the schedule runs each hour, at the same time in the first 10 minutes each time it is
+// created with the key.
+func WithStableJitter(schedule Schedule, max time.Duration, key string) Schedule {
+	sched := &jitterSchedule{
+		schedule: schedule,
+		max:      max,
+		key:      key,
+	}
+
+	if max > 0 {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		sched.offset = time.Duration(h.Sum64() % uint64(max))
+	}
+
+	return sched
+}
+
+// JitterSchedule ------------------------------------------------------------------
+
+type jitterSchedule struct {
+	schedule Schedule
+
+	// max is the maximum offset of the activations.
+	max time.Duration
+
+	// offset is the offset of the activations if random is false, derived from key.
+	offset time.Duration
+	key    string
+
+	// random reports whether each activation is delayed by its own offset, derived from
+	// seed and the time of the activation.
+	random bool
+	seed   uint64
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *jitterSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *jitterSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *jitterSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	sched, ok := withDSTPolicy(s.schedule, policy)
+	if !ok {
+		return s, false
+	}
+
+	set := *s
+	set.schedule = sched
+	return &set, true
+}
+
+func (s *jitterSchedule) startTimes(start time.Time) (Schedule, bool) {
+	sched, ok := startTimes(s.schedule, start)
+	if !ok {
+		return s, false
+	}
+
+	started := *s
+	started.schedule = sched
+	return &started, true
+}
+
+func (s *jitterSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	sched, ok := withPrecision(s.schedule, precision)
+	if !ok {
+		return s, false
+	}
+
+	raised := *s
+	raised.schedule = sched
+	return &raised, true
+}
+
+// String returns the @jitter descriptor of the schedule, with the key of stable jitters.
+func (s *jitterSchedule) String() string {
+	if s.random {
+		return fmt.Sprintf("@jitter %v %v", s.max, groups(s.schedule))
+	}
+
+	return fmt.Sprintf("@jitter %v %v %v", s.max, strconv.Quote(s.key), groups(s.schedule))
+}
+
+func (s *jitterSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *jitterSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *jitterSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first delayed activation after: after (field).
+//
+// returns the zero time if there is none.
+func (s *jitterSchedule) Next(after time.Time) time.Time {
+	var next time.Time
+
+	// activations up to max (field) before after (field) can be delayed past it, the
+	// activations after the earliest delayed one cant be delayed before it.
+	from := after.Add(-s.max)
+	for steps := 0; steps < combineSteps; steps++ {
+		t := nextAfter(s.schedule, from)
+		if t.IsZero() || (!next.IsZero() && t.After(next)) {
+			break
+		}
+
+		if delayed := t.Add(s.delay(t)); delayed.After(after) && (next.IsZero() || delayed.Before(next)) {
+			next = delayed
+		}
+		from = t
+	}
+
+	return next
+}
+
+// delay returns the offset of the activation at: t (field).
+func (s *jitterSchedule) delay(t time.Time) time.Duration {
+	if !s.random {
+		return s.offset
+	}
+
+	return randomDelay(s.seed, t, s.max)
+}
+
+// randomDelay returns an offset between 0 and max (field) derived from seed (field) and
+// the time: t (field), the same for the same seed and time.
+func randomDelay(seed uint64, t time.Time, max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[:8], seed)
+	binary.LittleEndian.PutUint64(b[8:], uint64(t.UnixNano()))
+
+	h := fnv.New64a()
+	h.Write(b[:])
+	return time.Duration(h.Sum64() % uint64(max))
+}
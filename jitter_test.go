@@ -0,0 +1,65 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithStableJitter(t *testing.T) {
+	after := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	first := WithStableJitter(EveryFixed(time.Hour), 10*time.Minute, "billing-report")
+	second := WithStableJitter(EveryFixed(time.Hour), 10*time.Minute, "billing-report")
+
+	offset := first.(*jitterSchedule).offset
+	if offset < 0 || offset >= 10*time.Minute {
+		t.Fatalf("got: %v want: between 0 and %v", offset, 10*time.Minute)
+	}
+
+	// the same key delays the activations by the same offset.
+	for i, hour := range []int{11, 12, 13} {
+		want := time.Date(2026, 10, 17, hour, 0, 0, 0, time.UTC).Add(offset)
+
+		if got := activate(first, after); !got.Equal(want) {
+			t.Fatalf("activation %v: got: %v want: %v", i, got, want)
+		}
+		if got := activate(second, after); !got.Equal(want) {
+			t.Fatalf("activation %v: got: %v want: %v", i, got, want)
+		}
+		after = want
+	}
+
+	// an activation delayed past now runs.
+	before := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC).Add(offset - time.Nanosecond)
+	sched := WithStableJitter(EveryFixed(time.Hour), 10*time.Minute, "billing-report")
+	if got, want := activate(sched, before), before.Add(time.Nanosecond); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	other := WithStableJitter(EveryFixed(time.Hour), 10*time.Minute, "audit-report")
+	if other.(*jitterSchedule).offset == offset {
+		t.Fatalf("got: same offset %v for different keys", offset)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	// the activation of 10:00 cant be delayed past after (field).
+	after := time.Date(2026, 10, 17, 10, 10, 0, 0, time.UTC)
+	sched := WithJitter(EveryFixed(time.Hour), 10*time.Minute)
+
+	for i := 0; i < 100; i++ {
+		hour := time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour)
+
+		got := activate(sched, after)
+		if got.Before(hour) || !got.Before(hour.Add(10*time.Minute)) {
+			t.Fatalf("activation %v: got: %v want: between %v and %v", i, got, hour, hour.Add(10*time.Minute))
+		}
+		after = got
+	}
+
+	// without a maximum the activations arent delayed.
+	sched = WithJitter(EveryFixed(time.Hour), 0)
+	if got, want := activate(sched, after), time.Date(2026, 10, 21, 15, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
@@ -15,8 +15,16 @@ type Node struct {
 	// The job which needs to be ran.
 	Job *Job
 
+	// The time of the next activation of the node, kept by the scheduler.
+	NextRun time.Time
+
 	// The ptr to the next node.
 	next *Node
+
+	// The ptr to the previous node and to the head of the list holding the node, used by
+	// the timing wheels.
+	prev *Node
+	list **Node
 }
 
 // linked list will point to the root node.
@@ -32,7 +40,7 @@ func (l *linkedList) NextCycle(now time.Time) time.Duration {
 	}
 
 	// the head node has the shortest duration.
-	if v := l.head.Schedule.Calculate(now); v < 0 {
+	if v := l.head.NextRun.Sub(now); v < 0 {
 		return 0
 	} else {
 		return v
@@ -45,7 +53,7 @@ func (l *linkedList) NextCycle(now time.Time) time.Duration {
 func (l *linkedList) RunNow(now time.Time) (nodes []*Node) {
 	ptr := l.head
 	for i := 0; i < l.len; i++ {
-		if ptr.Schedule.Calculate(now) <= 0 {
+		if !ptr.NextRun.After(now) {
 			nodes = append(nodes, ptr)
 		}
 
@@ -58,16 +66,24 @@ func (l *linkedList) RunNow(now time.Time) (nodes []*Node) {
 // ScheduleJob will add the node in the respective position
 // based on the schedule.
 //
-// no-op if node is nil.
+// no-op if node is nil or its schedule has no further activations.
 func (l *linkedList) AddNode(now time.Time, node *Node) {
 	// return if pointer is nil.
 	if node == nil {
 		return
 	}
 
-	// if inserting a cyclic schedule, move to next activation time.
-	if sched, ok := node.Schedule.(CyclicSchedule); ok {
-		sched.MoveNextAvtivation(now)
+	node.NextRun = nextRun(now, node.Schedule)
+	l.insert(node)
+}
+
+// insert adds the node (field) in the respective position based on its next
+// activation.
+//
+// no-op if the node has no next activation.
+func (l *linkedList) insert(node *Node) {
+	if node.NextRun.IsZero() {
+		return
 	}
 
 	// if head is nil add node as the head.
@@ -77,18 +93,16 @@ func (l *linkedList) AddNode(now time.Time, node *Node) {
 		return
 	}
 
-	durInsertNode := node.Schedule.Calculate(now)
-
 	ptr := l.head
 	for i := 0; i < l.len; i++ {
-		if durInsertNode <= ptr.Schedule.Calculate(now) {
+		if !node.NextRun.After(ptr.NextRun) {
 			// this can only happen for the first node as all the other nodes are already checked
 			// for in the next condition.
 			l.len++
 			l.addFirst(node)
 			return
 
-		} else if ptr.next == nil || durInsertNode <= ptr.next.Schedule.Calculate(now) {
+		} else if ptr.next == nil || !node.NextRun.After(ptr.next.NextRun) {
 			// add node after current node if next ptr is either nill (end of list)
 			// or duration of the ptr to the next node is less then desired node.
 			l.len++
@@ -109,12 +123,12 @@ func (l *linkedList) RemoveNode(id int) {
 		return
 	}
 
+	var prev *Node
 	ptr := l.head
 	for i := 0; i < l.len; i++ {
 		if ptr.Id == id {
-			if i > 0 {
-				prevNode := l.getAt(i - 1)
-				prevNode.next = l.getAt(i).next
+			if prev != nil {
+				prev.next = ptr.next
 			} else {
 				l.head = ptr.next
 			}
@@ -122,8 +136,22 @@ func (l *linkedList) RemoveNode(id int) {
 			return
 		}
 
+		prev, ptr = ptr, ptr.next
+	}
+}
+
+// node returns the node with the given id, nil if not found.
+func (l *linkedList) node(id int) *Node {
+	ptr := l.head
+	for i := 0; i < l.len; i++ {
+		if ptr.Id == id {
+			return ptr
+		}
+
 		ptr = ptr.next
 	}
+
+	return nil
 }
 
 // GetAll returns all the jobs in the storage system.
@@ -138,15 +166,22 @@ func (l *linkedList) GetAll() (jobs []*Node) {
 }
 
 // Clean removes the node (field) and re-calculates appropriate nodes.
+//
+// nodes which were removed arent added back.
 func (l *linkedList) Clean(now time.Time, nodes []*Node) {
 	for _, node := range nodes {
+		if l.node(node.Id) != node {
+			continue
+		}
+
 		switch node.Schedule.(type) {
 		case CyclicSchedule:
 			// remove the ran node node.
 			l.RemoveNode(node.Id)
 
 			// then re-add the cyclic node.
-			l.AddNode(now, node)
+			node.NextRun = rescheduled(now, node)
+			l.insert(node)
 
 		case Schedule:
 			// remove nodes with constand schedule.
@@ -155,6 +190,36 @@ func (l *linkedList) Clean(now time.Time, nodes []*Node) {
 	}
 }
 
+// nextRun returns the first activation of schedule (field) for a node added at now (field).
+//
+// cyclic schedules run after now (field), schedules running once run at their
+// activation, straight away if it passed. returns the zero time if there is none.
+func nextRun(now time.Time, schedule Schedule) time.Time {
+	if _, ok := schedule.(CyclicSchedule); ok {
+		return nextAfter(schedule, now)
+	}
+
+	d := schedule.Calculate(now)
+	if d == never {
+		return time.Time{}
+	}
+
+	return now.Add(d)
+}
+
+// rescheduled returns the activation of the node (field) following the one which ran.
+//
+// the activation is computed from the one which ran rather than now (field), so the
+// activations dont drift by the time taken to wake up. activations which passed while
+// the cronjob was busy are skipped.
+func rescheduled(now time.Time, node *Node) time.Time {
+	if next := nextAfter(node.Schedule, node.NextRun); next.After(now) {
+		return next
+	}
+
+	return nextAfter(node.Schedule, now)
+}
+
 // addFirst changes the head of the linked list.
 func (l *linkedList) addFirst(node *Node) {
 	ptrTemp := l.head
@@ -169,19 +234,3 @@ func (n *Node) insertAfter(node *Node) {
 	n.next = node
 	node.next = prtTemp
 }
-
-func (l *linkedList) getAt(pos int) *Node {
-	if pos < 0 {
-		return l.head
-	}
-
-	if pos > (l.len - 1) {
-		return nil
-	}
-
-	ptr := l.head
-	for i := 0; i < pos; i++ {
-		ptr = ptr.next
-	}
-	return ptr
-}
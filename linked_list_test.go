@@ -103,6 +103,21 @@ func TestAddNode(t *testing.T) {
 		}
 	})
 
+	t.Run("Test Exhausted Node", func(t *testing.T) {
+		now := time.Now()
+
+		sched, err := Parse("0 0 30 2 *")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		l := newWithNode(now, &Node{Schedule: sched})
+
+		if got, want := len(l.GetAll()), 0; got != want {
+			t.Fatalf("got: %v want: %v", got, want)
+		}
+	})
+
 	t.Run("Test Change Head Node", func(t *testing.T) {
 		now := time.Now()
 
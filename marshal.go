@@ -0,0 +1,254 @@
+package cronjob
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// textParser parses the text of the schedules, see (*Parser).Parse.
+var textParser = NewParser(SecondOptional | MinuteField | HourField | DomField | MonthField | DowField | Descriptor)
+
+var (
+	registryMu sync.RWMutex
+
+	// registeredTypes maps the descriptors of the registered schedules to their type.
+	registeredTypes = map[string]reflect.Type{}
+
+	// registeredTags maps the types of the registered schedules to their descriptor.
+	registeredTags = map[reflect.Type]string{}
+)
+
+// RegisterSchedule registers the type of schedule (field) under the descriptor
+// "@" + tag (field), to marshal and unmarshal it with the built-in schedules.
+//
+// schedule (field) is a Schedule or a NextSchedule implementing encoding.TextMarshaler,
+// its pointer implementing encoding.TextUnmarshaler. the schedule is written as
+// "@<tag> <text>" and parsed back by Parse, with a parser accepting descriptors, on its
+// own or combined with other schedules.
+//
+// example:
+//
+//	type quarterly struct{ day int }
+//
+//	func (q quarterly) Next(after time.Time) time.Time { ... }
+//	func (q quarterly) MarshalText() ([]byte, error) { ... }
+//	func (q *quarterly) UnmarshalText(text []byte) error { ... }
+//
+//	cronjob.RegisterSchedule("quarterly", quarterly{})
+//	cronjob.Parse("@union (@quarterly 15) (0 9 1 1 *)")
+//
+// panics if the tag is used by another schedule or a built-in descriptor, or if the
+// schedule doesnt implement the interfaces.
+func RegisterSchedule(tag string, schedule interface{}) {
+	name := "@" + strings.ToLower(tag)
+	if tag == "" || strings.ContainsAny(tag, " \t\n()\"") {
+		panic(fmt.Sprintf("cronjob: invalid schedule tag: %q", tag))
+	}
+	if builtinDescriptor(name) {
+		panic(fmt.Sprintf("cronjob: schedule tag %q is a built-in descriptor", tag))
+	}
+
+	switch schedule.(type) {
+	case Schedule, NextSchedule:
+	default:
+		panic(fmt.Sprintf("cronjob: %T isnt a schedule", schedule))
+	}
+	if _, ok := schedule.(encoding.TextMarshaler); !ok {
+		panic(fmt.Sprintf("cronjob: %T doesnt implement encoding.TextMarshaler", schedule))
+	}
+
+	typ := reflect.TypeOf(schedule)
+	if _, ok := newSchedule(typ).Interface().(encoding.TextUnmarshaler); !ok {
+		panic(fmt.Sprintf("cronjob: %T doesnt implement encoding.TextUnmarshaler", schedule))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if t, ok := registeredTypes[name]; ok && t != typ {
+		panic(fmt.Sprintf("cronjob: schedule tag %q registered twice", tag))
+	}
+	if n, ok := registeredTags[typ]; ok && n != name {
+		panic(fmt.Sprintf("cronjob: %T registered twice", schedule))
+	}
+
+	registeredTypes[name] = typ
+	registeredTags[typ] = name
+}
+
+// builtinDescriptor reports whether name (field) is a built-in descriptor.
+func builtinDescriptor(name string) bool {
+	switch name {
+	case "@reboot", "@every", "@rrule", "@iso8601", "@systemd":
+		return true
+	}
+
+	_, ok := descriptors[name]
+	return ok || argDescriptors[name] != nil
+}
+
+// newSchedule returns a pointer to a new value of the registered type: typ (field), the
+// pointer the text is unmarshaled into.
+func newSchedule(typ reflect.Type) reflect.Value {
+	if typ.Kind() == reflect.Ptr {
+		return reflect.New(typ.Elem())
+	}
+
+	return reflect.New(typ)
+}
+
+// parseRegistered parses value (field) into a new schedule of the type registered under
+// the descriptor: name (field).
+//
+// reports false if no schedule is registered under the descriptor.
+func parseRegistered(name, value string) (Schedule, bool, error) {
+	registryMu.RLock()
+	typ, ok := registeredTypes[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	ptr := newSchedule(typ)
+	if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+		return nil, true, err
+	}
+
+	sched := ptr.Interface()
+	if typ.Kind() != reflect.Ptr {
+		sched = ptr.Elem().Interface()
+	}
+
+	if s, ok := sched.(Schedule); ok {
+		return s, true, nil
+	}
+	return FromNext(sched.(NextSchedule)), true, nil
+}
+
+// scheduleText returns the text of schedule (field): the descriptor of registered
+// schedules and the String method of the built-in ones.
+//
+// schedules which arent registered or fail to marshal are written with fmt.Sprint, the
+// text doesnt parse back.
+func scheduleText(schedule interface{}) string {
+	registryMu.RLock()
+	name, ok := registeredTags[reflect.TypeOf(schedule)]
+	registryMu.RUnlock()
+
+	if !ok {
+		return fmt.Sprint(schedule)
+	}
+
+	text, err := schedule.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return fmt.Sprint(schedule)
+	}
+
+	if len(text) == 0 {
+		return name
+	}
+	return name + " " + string(text)
+}
+
+// marshalText returns the text of schedule (field).
+//
+// returns an error if the text doesnt parse back to a schedule, when the schedule holds a
+// schedule which isnt built-in or registered.
+func marshalText(schedule Schedule) ([]byte, error) {
+	text := scheduleText(schedule)
+	if _, err := textParser.Parse(text); err != nil {
+		return nil, fmt.Errorf("cronjob: failed to marshal %T: %w", schedule, err)
+	}
+
+	return []byte(text), nil
+}
+
+// marshalJSON returns the text of schedule (field) as a json string.
+func marshalJSON(schedule Schedule) ([]byte, error) {
+	text, err := marshalText(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+// unmarshalText parses text (field) into the built-in schedule: dst (field).
+//
+// returns an error if the text is the one of another type of schedule.
+func unmarshalText(dst Schedule, text []byte) error {
+	sched, err := textParser.Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	src := reflect.ValueOf(sched)
+	if src.Type() != reflect.TypeOf(dst) {
+		return fmt.Errorf("cronjob: %q: cant unmarshal %T into %T", text, sched, dst)
+	}
+
+	reflect.ValueOf(dst).Elem().Set(src.Elem())
+	return nil
+}
+
+// ScheduleValue holds a schedule: Schedule (field) to marshal and unmarshal it as text
+// or json, with the built-in and registered schedules (see RegisterSchedule).
+//
+// example:
+//
+//	type JobDefinition struct {
+//		Name     string                 `json:"name"`
+//		Schedule cronjob.ScheduleValue  `json:"schedule"`
+//	}
+//
+// the schedule is written as a string: {"name": "report", "schedule": "0 9 * * 1-5"}.
+// yaml libraries using encoding.TextMarshaler and encoding.TextUnmarshaler write it the
+// same way.
+type ScheduleValue struct {
+	Schedule Schedule
+}
+
+func (v ScheduleValue) MarshalText() ([]byte, error) {
+	if v.Schedule == nil {
+		return nil, nil
+	}
+
+	return marshalText(v.Schedule)
+}
+
+func (v *ScheduleValue) UnmarshalText(text []byte) error {
+	sched, err := textParser.Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	v.Schedule = sched
+	return nil
+}
+
+func (v ScheduleValue) MarshalJSON() ([]byte, error) {
+	if v.Schedule == nil {
+		return []byte("null"), nil
+	}
+
+	return marshalJSON(v.Schedule)
+}
+
+func (v *ScheduleValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		v.Schedule = nil
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("cronjob: schedule should be a json string: %w", err)
+	}
+
+	return v.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,156 @@
+package cronjob
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// everyNthDay is a registered NextSchedule running at midnight UTC every n days of the
+// unix epoch.
+type everyNthDay struct {
+	n int
+}
+
+func (s everyNthDay) Next(after time.Time) time.Time {
+	days := floorDiv(after.Unix(), int64(day/time.Second))/int64(s.n)*int64(s.n) + int64(s.n)
+	return time.Unix(days*int64(day/time.Second), 0).UTC()
+}
+
+func (s everyNthDay) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(s.n)), nil
+}
+
+func (s *everyNthDay) UnmarshalText(text []byte) error {
+	n, err := strconv.Atoi(string(text))
+	if err != nil {
+		return err
+	}
+
+	s.n = n
+	return nil
+}
+
+func init() {
+	RegisterSchedule("every-nth-day", everyNthDay{})
+}
+
+func TestMarshalJSON(t *testing.T) {
+	cases := []struct {
+		schedule Schedule
+		expected string
+	}{
+		{spec("0 9 * * 1-5")(), `"0 9 * * 1-5"`},
+		{At(time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)), `"@at 2026-01-01T09:00:00Z"`},
+		{Every(time.Minute), `"@every 1m0s"`},
+		{EveryFixed(time.Hour), `"@every-fixed 1h0m0s"`},
+		{Union(spec("0 9 * * *")(), FromNext(everyNthDay{3})), `"@union (0 9 * * *) (@every-nth-day 3)"`},
+	}
+
+	for _, c := range cases {
+		got, err := json.Marshal(c.schedule)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != c.expected {
+			t.Fatalf("got: %s want: %s", got, c.expected)
+		}
+	}
+
+	// a schedule which isnt registered cant be parsed back.
+	if _, err := json.Marshal(Union(&legacyHourly{})); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	sched := Every(time.Second)
+	if err := sched.(encoding.TextUnmarshaler).UnmarshalText([]byte("@every 1m")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := sched.(*cyclicSchedule).every, time.Minute; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// the text of another type of schedule.
+	if err := sched.(encoding.TextUnmarshaler).UnmarshalText([]byte("0 9 * * *")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestScheduleValue(t *testing.T) {
+	type definition struct {
+		Name     string        `json:"name"`
+		Schedule ScheduleValue `json:"schedule"`
+	}
+
+	after := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	specs := []string{
+		"CRON_TZ=Europe/Paris 0 9 * * 1-5",
+		"@every-fixed 1h0m0s",
+		"@except (@every-nth-day 2) (0 0 * * 0,6)",
+		"@every-nth-day 7",
+	}
+
+	for _, spec := range specs {
+		var def definition
+		if err := json.Unmarshal([]byte(`{"name": "report", "schedule": `+strconv.Quote(spec)+`}`), &def); err != nil {
+			t.Fatalf("%q: %v", spec, err)
+		}
+
+		data, err := json.Marshal(def)
+		if err != nil {
+			t.Fatalf("%q: %v", spec, err)
+		}
+
+		var got definition
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+
+		if got, want := Preview(got.Schedule.Schedule, after, 5), Preview(def.Schedule.Schedule, after, 5); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got: %v want: %v", data, got, want)
+		}
+	}
+
+	var def definition
+	if err := json.Unmarshal([]byte(`{"schedule": null}`), &def); err != nil || def.Schedule.Schedule != nil {
+		t.Fatalf("got: %v, %v want: nil", def.Schedule.Schedule, err)
+	}
+	if err := json.Unmarshal([]byte(`{"schedule": "@every-nth-day x"}`), &def); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRegisterSchedule(t *testing.T) {
+	cases := []struct {
+		tag      string
+		schedule interface{}
+	}{
+		{"daily", everyNthDay{}},
+		{"every-nth-day", midnights{}},
+		{"every nth day", everyNthDay{}},
+		{"nth-day", everyNthDay{}},
+		{"midnights", midnights{}},
+	}
+
+	for _, c := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%q: expected panic", c.tag)
+				}
+			}()
+
+			RegisterSchedule(c.tag, c.schedule)
+		}()
+	}
+
+	// registering the same schedule again is fine.
+	RegisterSchedule("every-nth-day", everyNthDay{})
+}
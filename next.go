@@ -0,0 +1,124 @@
+package cronjob
+
+import (
+	"time"
+)
+
+// FromNext returns a schedule which runs at the activations of schedule (field), to
+// use a NextSchedule with the cronjob.
+//
+// example:
+//
+//	type daily struct{}
+//
+//	func (daily) Next(after time.Time) time.Time {
+//		return after.Truncate(24 * time.Hour).Add(24 * time.Hour)
+//	}
+//
+//	(*CronJob).AddFunc(foo, cronjob.FromNext(daily{}))
+func FromNext(schedule NextSchedule) Schedule {
+	if sched, ok := schedule.(Schedule); ok {
+		return sched
+	}
+
+	return &nextSchedule{
+		schedule: schedule,
+	}
+}
+
+// AsNext returns schedule (field) as a NextSchedule.
+//
+// schedules which dont implement NextSchedule are adapted: cyclic schedules are moved to
+// compute their activations, so the adapter isnt safe to share or preview while the
+// schedule is in use.
+func AsNext(schedule Schedule) NextSchedule {
+	if sched, ok := schedule.(NextSchedule); ok {
+		return sched
+	}
+
+	return &legacySchedule{
+		schedule: schedule,
+	}
+}
+
+// nextAfter returns the first activation of schedule (field) after: after (field).
+//
+// returns the zero time if there are no further activations.
+func nextAfter(schedule Schedule, after time.Time) time.Time {
+	t := AsNext(schedule).Next(after)
+	if !t.After(after) {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// nextFrom returns the first activation of schedule (field) which isnt before
+// from (field).
+func nextFrom(schedule Schedule, from time.Time) time.Time {
+	return nextAfter(schedule, from.Add(-time.Nanosecond))
+}
+
+// NextSchedule ------------------------------------------------------------------
+
+// nextSchedule adapts a NextSchedule to the Schedule interfaces.
+type nextSchedule struct {
+	schedule NextSchedule
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *nextSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *nextSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *nextSchedule) String() string {
+	return scheduleText(s.schedule)
+}
+
+func (s *nextSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *nextSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *nextSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+func (s *nextSchedule) Next(after time.Time) time.Time {
+	return s.schedule.Next(after)
+}
+
+// LegacySchedule ------------------------------------------------------------------
+
+// legacySchedule adapts a Schedule to the NextSchedule interface.
+type legacySchedule struct {
+	schedule Schedule
+}
+
+// Next moves cyclic schedules to after: after (field) and returns their next activation,
+// other schedules return their activation if it is after after (field).
+func (s *legacySchedule) Next(after time.Time) time.Time {
+	if sched, ok := s.schedule.(CyclicSchedule); ok {
+		sched.MoveNextAvtivation(after)
+	}
+
+	d := s.schedule.Calculate(after)
+	if d == never || d <= 0 {
+		return time.Time{}
+	}
+
+	return after.Add(d)
+}
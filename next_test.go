@@ -0,0 +1,90 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+// midnights is a NextSchedule running each day at midnight UTC.
+type midnights struct{}
+
+func (midnights) Next(after time.Time) time.Time {
+	return after.UTC().Truncate(day).Add(day)
+}
+
+// legacyHourly is a CyclicSchedule running each hour, without Next.
+type legacyHourly struct {
+	nextActivation time.Time
+}
+
+func (s *legacyHourly) Calculate(now time.Time) time.Duration {
+	return s.nextActivation.Sub(now)
+}
+
+func (s *legacyHourly) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = now.Truncate(time.Hour).Add(time.Hour)
+}
+
+func TestFromNext(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	sched := FromNext(midnights{})
+	if got, want := activate(sched, now), time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	l := newWithNode(now, &Node{Schedule: sched})
+	if got, want := l.NextCycle(now), 13*time.Hour+55*time.Minute; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// schedules implementing Schedule are returned as is.
+	every := Every(time.Hour)
+	if got := FromNext(every.(NextSchedule)); got != every {
+		t.Fatalf("got: %v want: %v", got, every)
+	}
+}
+
+func TestAsNext(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		schedule Schedule
+		expected string
+	}{
+		{"built-in", EveryFixed(time.Hour), "2026-10-17T11:00:00Z"},
+		{"cyclic", &legacyHourly{}, "2026-10-17T11:00:00Z"},
+		{"constant", At(now.Add(time.Minute)), "2026-10-17T10:06:00Z"},
+		{"constant passed", At(now), ""},
+	}
+
+	for _, c := range cases {
+		if got, want := AsNext(c.schedule).Next(now), mustParseTime(t, c.expected); !got.Equal(want) {
+			t.Fatalf("%v: got: %v want: %v", c.name, got, want)
+		}
+	}
+}
+
+func TestSharedSchedule(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	sched := EveryFixed(time.Hour)
+	n1 := &Node{Id: 1, Schedule: sched}
+	n2 := &Node{Id: 2, Schedule: sched}
+
+	l := newWithNode(now, n1)
+	l.AddNode(now.Add(time.Hour), n2)
+
+	// each node keeps its own next activation.
+	if got, want := n1.NextRun, time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := n2.NextRun, time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	if got, want := len(l.RunNow(time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC))), 1; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
@@ -0,0 +1,1229 @@
+package cronjob
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bounds represents the range of values a cron field accepts.
+type bounds struct {
+	min, max uint
+
+	// names maps the lower case aliases of the field to their values.
+	names map[string]uint
+}
+
+var (
+	secondBounds = bounds{0, 59, nil}
+	minuteBounds = bounds{0, 59, nil}
+	hourBounds   = bounds{0, 23, nil}
+	domBounds    = bounds{1, 31, nil}
+	monthBounds  = bounds{1, 12, map[string]uint{
+		"jan": 1,
+		"feb": 2,
+		"mar": 3,
+		"apr": 4,
+		"may": 5,
+		"jun": 6,
+		"jul": 7,
+		"aug": 8,
+		"sep": 9,
+		"oct": 10,
+		"nov": 11,
+		"dec": 12,
+	}}
+	// 7 is accepted as an alias for sunday.
+	dowBounds = bounds{0, 7, map[string]uint{
+		"sun": 0,
+		"mon": 1,
+		"tue": 2,
+		"wed": 3,
+		"thu": 4,
+		"fri": 5,
+		"sat": 6,
+	}}
+)
+
+// ParseOption configures which fields and features a parser accepts.
+type ParseOption int
+
+const (
+	// SecondField accepts a leading seconds field.
+	SecondField ParseOption = 1 << iota
+
+	// SecondOptional accepts an optional leading seconds field.
+	SecondOptional
+
+	// MinuteField accepts a minutes field.
+	MinuteField
+
+	// HourField accepts an hours field.
+	HourField
+
+	// DomField accepts a day of month field.
+	DomField
+
+	// MonthField accepts a month field.
+	MonthField
+
+	// DowField accepts a day of week field.
+	DowField
+
+	// DowOptional accepts an optional trailing day of week field.
+	DowOptional
+
+	// Descriptor accepts descriptors such as @daily or @every 1h.
+	Descriptor
+)
+
+// places holds the fields of a spec in order, with their bounds and the value used
+// when the parser doesnt accept them.
+var places = []struct {
+	option   ParseOption
+	bounds   bounds
+	fallback string
+}{
+	{SecondField, secondBounds, "0"},
+	{MinuteField, minuteBounds, "0"},
+	{HourField, hourBounds, "0"},
+	{DomField, domBounds, "*"},
+	{MonthField, monthBounds, "*"},
+	{DowField, dowBounds, "*"},
+}
+
+// descriptors maps the descriptors to their equivalent 6 field spec.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// standardParser parses the standard 5 field cron expressions and descriptors.
+var standardParser = NewParser(MinuteField | HourField | DomField | MonthField | DowField | Descriptor)
+
+// Parser parses cron expressions with a configurable set of fields.
+type Parser struct {
+	options ParseOption
+}
+
+// NewParser returns a parser which accepts the fields and features in options (field).
+//
+// fields not accepted by the parser default to 0 for seconds, minutes and hours and to
+// "*" for the rest. only one of SecondOptional and DowOptional can be used.
+//
+//	p := cronjob.NewParser(cronjob.SecondOptional | cronjob.MinuteField | cronjob.HourField)
+//	p.Parse("30 */5 *")
+//
+// parses a schedule running every 5 minutes at the 30th second.
+func NewParser(options ParseOption) Parser {
+	if options&SecondOptional > 0 {
+		options |= SecondField
+	}
+	if options&DowOptional > 0 {
+		options |= DowField
+	}
+
+	return Parser{options}
+}
+
+// Parse parses a standard 5 field cron expression and returns a schedule which runs
+// on every minute matched by it.
+//
+// the fields are respectively: minute, hour, day of month, month and day of week.
+//
+// each field accepts "*", single values, ranges "1-5", steps "*/15", "10-40/10", "5/15"
+// and comma separated lists of the above. month and day of week fields also accept the
+// first 3 letters of their english names (case insensitive), sunday can be written as
+// 0 or 7.
+//
+// if both day of month and day of week are restricted (none of them starts with "*")
+// the schedule runs when either of them matches, like in posix cron:
+//
+//	cronjob.Parse("30 2 1,15 * 1-5")
+//
+// runs at 02:30 on the 1st, the 15th and on every weekday.
+//
+// descriptors are also accepted:
+//
+//	@yearly (or @annually)  runs at midnight on the 1st of january.
+//	@monthly                runs at midnight on the 1st of every month.
+//	@weekly                 runs at midnight on every sunday.
+//	@daily (or @midnight)   runs at midnight every day.
+//	@hourly                 runs at the start of every hour.
+//	@every <duration>       runs in constant increments of duration, see Every.
+//	@reboot                 runs once when the cronjob starts, see WithRunOnStart.
+//
+// @reboot keeps running on start in InLocation, and in Union along with the other
+// schedules.
+//
+// the String method of the built-in schedules returns a spec parsed back to the same
+// schedule, using the descriptors:
+//
+//	@at <time>                         see At, times are written in RFC 3339.
+//	@every-fixed <duration> [<time>]   see EveryFixed and EveryFixedFrom.
+//	@rrule <rule>                      see ParseRRule.
+//	@iso8601 <interval>                see ParseISO8601Interval.
+//	@systemd <calendar event>          see ParseSystemdCalendar.
+//	@solar <event> <lat> <lon> [<offset>]
+//	@union (<spec>) (<spec>)...        also @intersect.
+//	@except (<spec>) (<spec>)
+//	@between <time> <time> (<spec>)    "*" leaves a side of the window open.
+//	@times <n> <time> (<spec>)         see TimesFrom.
+//	@jitter <max> ["<key>"] (<spec>)   see WithJitter and WithStableJitter.
+//	@business-days (<calendar>) (<spec>)
+//	@roll-forward (<calendar>) (<spec>)
+//	@roll-backward (<calendar>) (<spec>)
+//	@dst-skip (<spec>)                 see WithDSTPolicy.
+//	@backoff-exponential <base> <max> <factor> [<jitter>]
+//	@backoff-fibonacci <base> <max> [<jitter>]
+//
+// specs with a seconds field are written with 6 fields, a parser with SecondOptional
+// parses them back.
+//
+// the quartz extensions are supported in the day of month and day of week fields:
+//
+//	?      same as "*".
+//	L      day of month: the last day of the month, L-3 is the 3rd day before it.
+//	       day of week: saturday, 5L is the last friday of the month.
+//	W      day of month: 15W is the weekday (monday to friday) nearest to the 15th
+//	       in the same month, LW is the last weekday of the month.
+//	#      day of week: 2#3 is the 3rd tuesday of the month.
+//
+// unlike quartz, days of week are numbered from 0 (sunday) to 6 (saturday).
+//
+// times skipped or repeated by daylight saving time are handled according to the
+// policy of the schedule, see WithDSTPolicy.
+func Parse(spec string) (Schedule, error) {
+	return standardParser.Parse(spec)
+}
+
+// ParseKeyed parses a standard 5 field cron expression like Parse, accepting the H token
+// which is replaced by a value derived from key (field).
+//
+// example:
+//
+//	cronjob.ParseKeyed("H H(8-11) * * *", "billing-report")
+//
+// runs every day at a minute between 08:00 and 11:59 derived from "billing-report", the
+// same one each time the spec is parsed with the key.
+//
+// the H token accepts a range: H(0-29) and a step: H/15, which runs every 15 minutes
+// starting from a minute derived from the key. in the day of month field H is at most 28
+// to run each month.
+func ParseKeyed(spec, key string) (Schedule, error) {
+	return standardParser.ParseKeyed(spec, key)
+}
+
+// Parse parses spec (field) according to the options of the parser.
+//
+// the spec can be prefixed with "CRON_TZ=<location>" or "TZ=<location>" to evaluate the
+// schedule in the location instead of the location used by the cronjob:
+//
+//	p.Parse("CRON_TZ=Asia/Tokyo 0 9 * * *")
+//
+// runs at 09:00 in tokyo.
+func (p Parser) Parse(spec string) (Schedule, error) {
+	return p.parse(spec, nil)
+}
+
+// ParseKeyed parses spec (field) according to the options of the parser, accepting the H
+// token which is replaced by a value derived from key (field), see ParseKeyed.
+func (p Parser) ParseKeyed(spec, key string) (Schedule, error) {
+	return p.parse(spec, &key)
+}
+
+// parse parses spec (field), replacing the H tokens with values derived from key (field)
+// if it isnt nil.
+func (p Parser) parse(spec string, key *string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	loc, spec, err := parseLocation(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// a location wrapping a schedule with its own location.
+	if _, rest, _ := parseLocation(spec); loc != nil && rest != spec {
+		sched, err := p.parse(spec, key)
+		if err != nil {
+			return nil, err
+		}
+		return InLocation(sched, loc), nil
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		if p.options&Descriptor == 0 {
+			return nil, fmt.Errorf("cronjob: descriptors not accepted: %q", spec)
+		}
+
+		sched, err := p.parseDescriptor(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if loc == nil {
+			return sched, nil
+		}
+
+		switch s := sched.(type) {
+		case *specSchedule:
+			s.location = loc
+		case *rebootSchedule:
+			// runs on start regardless of the location.
+		default:
+			sched = InLocation(sched, loc)
+		}
+		return sched, nil
+	}
+
+	fields, err := p.normalizeFields(strings.Fields(spec))
+	if err != nil {
+		return nil, fmt.Errorf("cronjob: %q: %w", spec, err)
+	}
+
+	if fields, err = hashFields(fields, key); err != nil {
+		return nil, fmt.Errorf("cronjob: %q: %w", spec, err)
+	}
+
+	sched, err := parseFields(fields)
+	if err != nil {
+		return nil, fmt.Errorf("cronjob: %q: %w", spec, err)
+	}
+	sched.location = loc
+
+	return sched, nil
+}
+
+// parseLocation parses the optional CRON_TZ= or TZ= prefix of spec (field), returning
+// the location and the rest of the spec.
+//
+// the location is nil if the spec has no prefix.
+func parseLocation(spec string) (*time.Location, string, error) {
+	if !strings.HasPrefix(spec, "CRON_TZ=") && !strings.HasPrefix(spec, "TZ=") {
+		return nil, spec, nil
+	}
+
+	i := strings.IndexAny(spec, " \t")
+	if i == -1 {
+		return nil, "", fmt.Errorf("cronjob: missing spec after location: %q", spec)
+	}
+
+	name := spec[strings.Index(spec, "=")+1 : i]
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("cronjob: failed to load location %q: %w", name, err)
+	}
+
+	return loc, strings.TrimSpace(spec[i:]), nil
+}
+
+// normalizeFields returns the 6 fields of the spec, filling in the fields which the
+// parser doesnt accept or which are optional and missing.
+func (p Parser) normalizeFields(fields []string) ([]string, error) {
+	var optionals int
+	if p.options&SecondOptional > 0 {
+		optionals++
+	}
+	if p.options&DowOptional > 0 {
+		optionals++
+	}
+	if optionals > 1 {
+		return nil, fmt.Errorf("only one of SecondOptional and DowOptional can be used")
+	}
+
+	var max int
+	for _, place := range places {
+		if p.options&place.option > 0 {
+			max++
+		}
+	}
+	min := max - optionals
+
+	if count := len(fields); count < min || count > max {
+		if min == max {
+			return nil, fmt.Errorf("expected %v fields, found %v", min, count)
+		}
+		return nil, fmt.Errorf("expected %v to %v fields, found %v", min, max, count)
+	}
+
+	// fill in the missing optional field.
+	if len(fields) < max {
+		switch {
+		case p.options&SecondOptional > 0:
+			fields = append([]string{places[0].fallback}, fields...)
+		case p.options&DowOptional > 0:
+			fields = append(fields, places[5].fallback)
+		}
+	}
+
+	normalized := make([]string, 0, len(places))
+	for _, place := range places {
+		if p.options&place.option > 0 {
+			normalized = append(normalized, fields[0])
+			fields = fields[1:]
+		} else {
+			normalized = append(normalized, place.fallback)
+		}
+	}
+
+	return normalized, nil
+}
+
+// hashFields replaces the H tokens of the 6 normalized fields: fields (field) with values
+// derived from key (field).
+func hashFields(fields []string, key *string) ([]string, error) {
+	hashed := make([]string, len(fields))
+	for i, field := range fields {
+		exprs := strings.Split(field, ",")
+		for j, expr := range exprs {
+			if expr != "H" && !strings.HasPrefix(expr, "H(") && !strings.HasPrefix(expr, "H/") {
+				continue
+			}
+
+			if key == nil {
+				return nil, fmt.Errorf("H requires a key, see ParseKeyed: %q", expr)
+			}
+
+			h := fnv.New64a()
+			h.Write([]byte(*key))
+			h.Write([]byte{byte(i)})
+
+			var err error
+			if exprs[j], err = hashRange(expr, places[i].bounds, i, h.Sum64()); err != nil {
+				return nil, err
+			}
+		}
+
+		hashed[i] = strings.Join(exprs, ",")
+	}
+
+	return hashed, nil
+}
+
+// hashRange replaces the H token of expr (field), the i-th (field) field, with a value
+// within the bounds: b (field) derived from hash (field).
+func hashRange(expr string, b bounds, i int, hash uint64) (string, error) {
+	// every day of month and day of week value needs to exist in every month and week.
+	switch i {
+	case 3:
+		b.max = 28
+	case 5:
+		b.max = 6
+	}
+
+	rangeAndStep := strings.Split(expr, "/")
+	if len(rangeAndStep) > 2 {
+		return "", fmt.Errorf("too many slashes: %q", expr)
+	}
+
+	if head := rangeAndStep[0]; head != "H" {
+		if !strings.HasSuffix(head, ")") {
+			return "", fmt.Errorf("expected H(min-max): %q", expr)
+		}
+
+		lowAndHigh := strings.Split(head[2:len(head)-1], "-")
+		if len(lowAndHigh) != 2 {
+			return "", fmt.Errorf("expected H(min-max): %q", expr)
+		}
+
+		min, err := parseValue(lowAndHigh[0], b)
+		if err != nil {
+			return "", err
+		}
+		max, err := parseValue(lowAndHigh[1], b)
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case min < b.min:
+			return "", fmt.Errorf("beginning of range (%v) below minimum (%v): %q", min, b.min, expr)
+		case max > b.max:
+			return "", fmt.Errorf("end of range (%v) above maximum (%v): %q", max, b.max, expr)
+		case min > max:
+			return "", fmt.Errorf("beginning of range (%v) beyond end of range (%v): %q", min, max, expr)
+		}
+		b.min, b.max = min, max
+	}
+
+	if len(rangeAndStep) == 1 {
+		return strconv.FormatUint(uint64(b.min)+hash%uint64(b.max-b.min+1), 10), nil
+	}
+
+	step, err := parseUint(rangeAndStep[1])
+	if err != nil {
+		return "", err
+	}
+	if step == 0 {
+		return "", fmt.Errorf("step of range should be a positive number: %q", expr)
+	}
+
+	// the first value is within the first step of the range.
+	first := step
+	if width := b.max - b.min + 1; width < first {
+		first = width
+	}
+
+	return fmt.Sprintf("%v-%v/%v", uint64(b.min)+hash%uint64(first), b.max, step), nil
+}
+
+// parseFields parses the 6 normalized fields of a spec.
+func parseFields(fields []string) (*specSchedule, error) {
+	var err error
+	field := func(i int) uint64 {
+		if err != nil {
+			return 0
+		}
+
+		var bits uint64
+		bits, err = parseField(fields[i], places[i].bounds)
+		return bits
+	}
+
+	sched := &specSchedule{
+		fields: fields,
+		second: field(0),
+		minute: field(1),
+		hour:   field(2),
+		month:  field(4),
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parseDomField(fields[3], sched); err != nil {
+		return nil, err
+	}
+	if err := parseDowField(fields[5], sched); err != nil {
+		return nil, err
+	}
+
+	return sched, nil
+}
+
+// parseDomField parses the day of month field, including the quartz extensions, into
+// sched (field).
+func parseDomField(field string, sched *specSchedule) error {
+	for _, expr := range strings.Split(field, ",") {
+		upper := strings.ToUpper(expr)
+
+		switch {
+		case expr == "?":
+			sched.dom |= bitRange(domBounds.min, domBounds.max, 1) | starBit
+
+		case upper == "L":
+			sched.lastDays |= 1 << 0
+
+		case upper == "LW":
+			sched.lastWeekday = true
+
+		case strings.HasPrefix(upper, "L-"):
+			n, err := parseUint(expr[2:])
+			if err != nil {
+				return err
+			}
+			if n < 1 || n > 30 {
+				return fmt.Errorf("offset from last day of month should be between 1 and 30: %q", expr)
+			}
+			sched.lastDays |= 1 << n
+
+		case strings.HasSuffix(upper, "W"):
+			n, err := parseUint(expr[:len(expr)-1])
+			if err != nil {
+				return err
+			}
+			if n < domBounds.min || n > domBounds.max {
+				return fmt.Errorf("day of nearest weekday should be between 1 and 31: %q", expr)
+			}
+			sched.nearestWeekdays |= 1 << n
+
+		default:
+			bits, err := parseRange(expr, domBounds)
+			if err != nil {
+				return err
+			}
+			sched.dom |= bits
+		}
+	}
+
+	return nil
+}
+
+// parseDowField parses the day of week field, including the quartz extensions, into
+// sched (field).
+func parseDowField(field string, sched *specSchedule) error {
+	for _, expr := range strings.Split(field, ",") {
+		upper := strings.ToUpper(expr)
+
+		switch {
+		case expr == "?":
+			sched.dow |= bitRange(0, 6, 1) | starBit
+
+		// "L" on its own is the last day of the week: saturday.
+		case upper == "L":
+			sched.dow |= 1 << 6
+
+		case strings.HasSuffix(upper, "L"):
+			n, err := parseDow(expr[:len(expr)-1])
+			if err != nil {
+				return err
+			}
+			sched.lastDows |= 1 << n
+
+		case strings.Contains(expr, "#"):
+			parts := strings.Split(expr, "#")
+			if len(parts) != 2 {
+				return fmt.Errorf("too many hashes: %q", expr)
+			}
+
+			n, err := parseDow(parts[0])
+			if err != nil {
+				return err
+			}
+			k, err := parseUint(parts[1])
+			if err != nil {
+				return err
+			}
+			if k < 1 || k > 5 {
+				return fmt.Errorf("occurrence of day of week should be between 1 and 5: %q", expr)
+			}
+			sched.nthDows[n] |= 1 << k
+
+		default:
+			bits, err := parseRange(expr, dowBounds)
+			if err != nil {
+				return err
+			}
+			sched.dow |= foldSunday(bits)
+		}
+	}
+
+	return nil
+}
+
+// parseDow parses a single day of week, 7 is folded to sunday (0).
+func parseDow(expr string) (uint, error) {
+	n, err := parseValue(expr, dowBounds)
+	if err != nil {
+		return 0, err
+	}
+	if n > dowBounds.max {
+		return 0, fmt.Errorf("day of week (%v) above maximum (%v): %q", n, dowBounds.max, expr)
+	}
+
+	return n % 7, nil
+}
+
+// parseDescriptor parses the descriptor: spec (field), the schedules given as arguments
+// are parsed by the parser.
+func (p Parser) parseDescriptor(spec string) (Schedule, error) {
+	if fields, ok := descriptors[strings.ToLower(spec)]; ok {
+		return parseFields(strings.Fields(fields))
+	}
+
+	switch name, value := splitDescriptor(spec); name {
+	case "@reboot":
+		if value != "" {
+			break
+		}
+		return &rebootSchedule{}, nil
+
+	case "@every":
+		every, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("cronjob: failed to parse duration %q: %w", spec, err)
+		}
+		return Every(every), nil
+
+	case "@rrule":
+		return ParseRRule(value)
+
+	case "@iso8601":
+		return ParseISO8601Interval(value)
+
+	case "@systemd":
+		return ParseSystemdCalendar(value)
+
+	default:
+		parse, ok := argDescriptors[name]
+		if !ok {
+			sched, ok, err := parseRegistered(name, value)
+			if !ok {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("cronjob: %q: %w", spec, err)
+			}
+			return sched, nil
+		}
+
+		args, err := splitArgs(value)
+		if err != nil {
+			return nil, fmt.Errorf("cronjob: %q: %w", spec, err)
+		}
+
+		sched, err := parse(p, args)
+		if err != nil {
+			return nil, fmt.Errorf("cronjob: %q: %w", spec, err)
+		}
+		return sched, nil
+	}
+
+	return nil, fmt.Errorf("cronjob: unrecognized descriptor: %q", spec)
+}
+
+// argDescriptors maps the descriptors taking arguments to the functions parsing them.
+var argDescriptors map[string]func(Parser, []string) (Schedule, error)
+
+// the functions parse the schedules given as arguments, which can be descriptors.
+func init() {
+	argDescriptors = map[string]func(Parser, []string) (Schedule, error){
+		"@at": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 1, 1); err != nil {
+				return nil, err
+			}
+
+			at, err := parseTime(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return At(at), nil
+		},
+
+		"@every-fixed": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 1, 2); err != nil {
+				return nil, err
+			}
+
+			every, err := time.ParseDuration(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			var anchor time.Time
+			if len(args) == 2 {
+				if anchor, err = parseTime(args[1]); err != nil {
+					return nil, err
+				}
+			}
+			return EveryFixedFrom(every, anchor), nil
+		},
+
+		"@solar": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 3, 4); err != nil {
+				return nil, err
+			}
+
+			event, err := parseSolarEvent(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			var coordinate [2]float64
+			for i := range coordinate {
+				if coordinate[i], err = strconv.ParseFloat(args[i+1], 64); err != nil {
+					return nil, fmt.Errorf("invalid coordinate: %q", args[i+1])
+				}
+			}
+
+			var offset time.Duration
+			if len(args) == 4 {
+				if offset, err = time.ParseDuration(args[3]); err != nil {
+					return nil, err
+				}
+			}
+			return Solar(event, coordinate[0], coordinate[1], offset), nil
+		},
+
+		"@union": func(p Parser, args []string) (Schedule, error) {
+			schedules, err := p.parseArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return Union(schedules...), nil
+		},
+
+		"@intersect": func(p Parser, args []string) (Schedule, error) {
+			schedules, err := p.parseArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return Intersect(schedules...), nil
+		},
+
+		"@except": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 2, 2); err != nil {
+				return nil, err
+			}
+
+			schedules, err := p.parseArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return Except(schedules[0], schedules[1]), nil
+		},
+
+		"@between": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 3, 3); err != nil {
+				return nil, err
+			}
+
+			start, err := parseTime(args[0])
+			if err != nil {
+				return nil, err
+			}
+			end, err := parseTime(args[1])
+			if err != nil {
+				return nil, err
+			}
+
+			sched, err := p.Parse(args[2])
+			if err != nil {
+				return nil, err
+			}
+			return Between(start, end, sched), nil
+		},
+
+		"@times": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 3, 3); err != nil {
+				return nil, err
+			}
+
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid number of activations: %q", args[0])
+			}
+			start, err := parseTime(args[1])
+			if err != nil {
+				return nil, err
+			}
+
+			sched, err := p.Parse(args[2])
+			if err != nil {
+				return nil, err
+			}
+			return TimesFrom(n, start, sched), nil
+		},
+
+		"@jitter": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 2, 3); err != nil {
+				return nil, err
+			}
+
+			max, err := time.ParseDuration(args[0])
+			if err != nil {
+				return nil, err
+			}
+
+			sched, err := p.Parse(args[len(args)-1])
+			if err != nil {
+				return nil, err
+			}
+
+			if len(args) == 3 {
+				return WithStableJitter(sched, max, args[1]), nil
+			}
+			return WithJitter(sched, max), nil
+		},
+
+		"@backoff-exponential": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 3, 4); err != nil {
+				return nil, err
+			}
+
+			factor, err := strconv.ParseFloat(args[2], 64)
+			if err != nil {
+				return nil, err
+			}
+			return parseBackoff(args[:2], args[3:], factor)
+		},
+
+		"@backoff-fibonacci": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 2, 3); err != nil {
+				return nil, err
+			}
+			return parseBackoff(args[:2], args[2:], 0)
+		},
+
+		"@business-days": func(p Parser, args []string) (Schedule, error) {
+			calendar, sched, err := p.parseCalendarArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return OnBusinessDays(sched, calendar), nil
+		},
+
+		"@roll-forward": func(p Parser, args []string) (Schedule, error) {
+			calendar, sched, err := p.parseCalendarArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return RollForward(sched, calendar), nil
+		},
+
+		"@roll-backward": func(p Parser, args []string) (Schedule, error) {
+			calendar, sched, err := p.parseCalendarArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			return RollBackward(sched, calendar), nil
+		},
+
+		"@dst-skip": func(p Parser, args []string) (Schedule, error) {
+			if err := expectArgs(args, 1, 1); err != nil {
+				return nil, err
+			}
+
+			sched, err := p.Parse(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return WithDSTPolicy(sched, DSTSkip), nil
+		},
+	}
+}
+
+// parseArgs parses each of args (field) as a schedule.
+func (p Parser) parseArgs(args []string) ([]Schedule, error) {
+	schedules := make([]Schedule, 0, len(args))
+	for _, arg := range args {
+		sched, err := p.Parse(arg)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, sched)
+	}
+
+	return schedules, nil
+}
+
+// parseCalendarArgs parses the arguments: args (field) of the business days descriptors,
+// a calendar and a schedule.
+func (p Parser) parseCalendarArgs(args []string) (*Calendar, Schedule, error) {
+	if err := expectArgs(args, 2, 2); err != nil {
+		return nil, nil, err
+	}
+
+	calendarArgs, err := splitArgs(args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	calendar, err := parseCalendar(calendarArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sched, err := p.Parse(args[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return calendar, sched, nil
+}
+
+// parseBackoff parses the base and max intervals: intervals (field) and the optional
+// jitter (field) of a backoff schedule growing by factor (field).
+func parseBackoff(intervals, jitter []string, factor float64) (Schedule, error) {
+	var bounds [2]time.Duration
+	for i := range bounds {
+		d, err := time.ParseDuration(intervals[i])
+		if err != nil {
+			return nil, err
+		}
+		bounds[i] = d
+	}
+
+	var confs []BackoffConf
+	if len(jitter) > 0 {
+		j, err := strconv.ParseFloat(jitter[0], 64)
+		if err != nil {
+			return nil, err
+		}
+		confs = append(confs, WithBackoffJitter(j))
+	}
+
+	if factor > 0 {
+		return ExponentialBackoff(bounds[0], bounds[1], factor, confs...), nil
+	}
+	return FibonacciBackoff(bounds[0], bounds[1], confs...), nil
+}
+
+// expectArgs checks that there are between min (field) and max (field) args (field).
+func expectArgs(args []string, min, max int) error {
+	if count := len(args); count < min || count > max {
+		if min == max {
+			return fmt.Errorf("expected %v arguments, found %v", min, count)
+		}
+		return fmt.Errorf("expected %v to %v arguments, found %v", min, max, count)
+	}
+
+	return nil
+}
+
+// splitArgs splits the arguments of a descriptor: value (field).
+//
+// arguments are separated by spaces, arguments in parentheses (schedules) and double
+// quotes (keys) can hold spaces. the parentheses and quotes are removed.
+func splitArgs(value string) ([]string, error) {
+	var args []string
+	for i := 0; i < len(value); {
+		switch value[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+
+		case '(':
+			end, err := closingParen(value, i)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, strings.TrimSpace(value[i+1:end]))
+			i = end + 1
+
+		case '"':
+			end, err := closingQuote(value, i)
+			if err != nil {
+				return nil, err
+			}
+			arg, err := strconv.Unquote(value[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted argument: %q", value[i:end+1])
+			}
+			args = append(args, arg)
+			i = end + 1
+
+		default:
+			end := strings.IndexAny(value[i:], " \t\n\r")
+			if end == -1 {
+				end = len(value) - i
+			}
+			args = append(args, value[i:i+end])
+			i += end
+		}
+	}
+
+	return args, nil
+}
+
+// closingParen returns the index of the parenthesis closing the one at: open (field) in
+// value (field).
+func closingParen(value string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(value); i++ {
+		switch value[i] {
+		case '"':
+			end, err := closingQuote(value, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unbalanced parentheses: %q", value[open:])
+}
+
+// closingQuote returns the index of the double quote closing the one at: open (field) in
+// value (field).
+func closingQuote(value string, open int) (int, error) {
+	for i := open + 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated quote: %q", value[open:])
+}
+
+// parseTime parses the time argument: arg (field) of a descriptor, "*" is the zero time.
+func parseTime(arg string) (time.Time, error) {
+	if arg == "*" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339Nano, arg)
+}
+
+// formatTime formats t (field) as a time argument of a descriptor.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "*"
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+// groups formats the schedules: schedules (field) as arguments of a descriptor.
+func groups(schedules ...Schedule) string {
+	args := make([]string, 0, len(schedules))
+	for _, sched := range schedules {
+		args = append(args, "("+scheduleText(sched)+")")
+	}
+
+	return strings.Join(args, " ")
+}
+
+// withLocation prefixes spec (field) with the location: loc (field), if it isnt nil.
+func withLocation(loc *time.Location, spec string) string {
+	if loc == nil {
+		return spec
+	}
+
+	return "CRON_TZ=" + loc.String() + " " + spec
+}
+
+// withDST wraps spec (field) in the @dst-skip descriptor if policy (field) is DSTSkip.
+func withDST(policy DSTPolicy, spec string) string {
+	if policy != DSTSkip {
+		return spec
+	}
+
+	return "@dst-skip (" + spec + ")"
+}
+
+// splitDescriptor splits the descriptor into its lower case name and its value at the
+// first space or tab.
+func splitDescriptor(spec string) (string, string) {
+	i := strings.IndexAny(spec, " \t")
+	if i == -1 {
+		return strings.ToLower(spec), ""
+	}
+
+	return strings.ToLower(spec[:i]), strings.TrimSpace(spec[i+1:])
+}
+
+// parseField parses a comma separated list of ranges into a set of bits.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, expr := range strings.Split(field, ",") {
+		bit, err := parseRange(expr, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= bit
+	}
+
+	return bits, nil
+}
+
+// parseRange parses a single value, range or step expression into a set of bits.
+//
+// expressions starting with "*" also set the star bit.
+func parseRange(expr string, b bounds) (uint64, error) {
+	var (
+		start, end, step uint
+		extra            uint64
+		err              error
+	)
+
+	rangeAndStep := strings.Split(expr, "/")
+	lowAndHigh := strings.Split(rangeAndStep[0], "-")
+	single := len(lowAndHigh) == 1
+
+	if lowAndHigh[0] == "*" {
+		if !single {
+			return 0, fmt.Errorf("unexpected range after *: %q", expr)
+		}
+
+		start, end = b.min, b.max
+		extra = starBit
+	} else {
+		if start, err = parseValue(lowAndHigh[0], b); err != nil {
+			return 0, err
+		}
+
+		switch len(lowAndHigh) {
+		case 1:
+			end = start
+		case 2:
+			if end, err = parseValue(lowAndHigh[1], b); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("too many hyphens: %q", expr)
+		}
+	}
+
+	switch len(rangeAndStep) {
+	case 1:
+		step = 1
+	case 2:
+		if step, err = parseUint(rangeAndStep[1]); err != nil {
+			return 0, err
+		}
+
+		// "N/step" means "N-max/step".
+		if single {
+			end = b.max
+		}
+	default:
+		return 0, fmt.Errorf("too many slashes: %q", expr)
+	}
+
+	switch {
+	case start < b.min:
+		return 0, fmt.Errorf("beginning of range (%v) below minimum (%v): %q", start, b.min, expr)
+	case end > b.max:
+		return 0, fmt.Errorf("end of range (%v) above maximum (%v): %q", end, b.max, expr)
+	case start > end:
+		return 0, fmt.Errorf("beginning of range (%v) beyond end of range (%v): %q", start, end, expr)
+	case step == 0:
+		return 0, fmt.Errorf("step of range should be a positive number: %q", expr)
+	}
+
+	return bitRange(start, end, step) | extra, nil
+}
+
+// parseValue parses a number or one of the names of the field.
+func parseValue(expr string, b bounds) (uint, error) {
+	if b.names != nil {
+		if v, ok := b.names[strings.ToLower(expr)]; ok {
+			return v, nil
+		}
+	}
+
+	return parseUint(expr)
+}
+
+func parseUint(expr string) (uint, error) {
+	v, err := strconv.ParseUint(expr, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse number %q", expr)
+	}
+
+	return uint(v), nil
+}
+
+// bitRange returns the set of bits from start to end (inclusive) in increments of step.
+func bitRange(start, end, step uint) uint64 {
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+
+	return bits
+}
+
+// foldSunday moves the bit of sunday written as 7 to 0.
+func foldSunday(bits uint64) uint64 {
+	if bits&(1<<7) > 0 {
+		bits = bits&^(1<<7) | 1<<0
+	}
+
+	return bits
+}
@@ -0,0 +1,339 @@
+package cronjob
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseField(t *testing.T) {
+	cases := []struct {
+		expr     string
+		bounds   bounds
+		expected uint64
+	}{
+		{"5", minuteBounds, 1 << 5},
+		{"1,3", minuteBounds, 1<<1 | 1<<3},
+		{"1-3", minuteBounds, 1<<1 | 1<<2 | 1<<3},
+		{"10-40/10", minuteBounds, 1<<10 | 1<<20 | 1<<30 | 1<<40},
+		{"50/5", minuteBounds, 1<<50 | 1<<55},
+		{"*/6", hourBounds, starBit | 1<<0 | 1<<6 | 1<<12 | 1<<18},
+		{"*", monthBounds, starBit | bitRange(1, 12, 1)},
+		{"jan-mar,DEC", monthBounds, 1<<1 | 1<<2 | 1<<3 | 1<<12},
+		{"mon-fri", dowBounds, bitRange(1, 5, 1)},
+	}
+
+	for _, c := range cases {
+		got, err := parseField(c.expr, c.bounds)
+		if err != nil {
+			t.Fatalf("%q: %v", c.expr, err)
+		}
+
+		if want := c.expected; got != want {
+			t.Fatalf("%q: got: %b want: %b", c.expr, got, want)
+		}
+	}
+}
+
+func TestParseSundayAlias(t *testing.T) {
+	for _, spec := range []string{"0 0 * * 0", "0 0 * * 7", "0 0 * * sun"} {
+		sched, err := Parse(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := sched.(*specSchedule).dow, uint64(1<<0); got != want {
+			t.Fatalf("%q: got: %b want: %b", spec, got, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"1-2-3 * * * *",
+		"*/2/3 * * * *",
+		"*-5 * * * *",
+		"foo * * * *",
+		"* * * bar *",
+		"* * * L *",
+		"* * 5 * L-1",
+		"* * L-0 * *",
+		"* * L-31 * *",
+		"* * 0W * *",
+		"* * 32W * *",
+		"* * * * 8L",
+		"* * * * 1#0",
+		"* * * * 1#6",
+		"* * * * 1#2#3",
+		"* ? * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Fatalf("%q: expected error", spec)
+		}
+	}
+}
+
+func TestParserOptions(t *testing.T) {
+	now := time.Date(2022, 10, 7, 10, 5, 0, 0, time.UTC)
+
+	cases := []struct {
+		options  ParseOption
+		spec     string
+		expected time.Time
+	}{
+		// leading seconds field.
+		{
+			options:  SecondField | MinuteField | HourField | DomField | MonthField | DowField,
+			spec:     "30 5 10 * * *",
+			expected: time.Date(2022, 10, 7, 10, 5, 30, 0, time.UTC),
+		},
+
+		// optional seconds field, present.
+		{
+			options:  SecondOptional | MinuteField | HourField | DomField | MonthField | DowField,
+			spec:     "*/20 * * * * *",
+			expected: time.Date(2022, 10, 7, 10, 5, 20, 0, time.UTC),
+		},
+
+		// optional seconds field, missing.
+		{
+			options:  SecondOptional | MinuteField | HourField | DomField | MonthField | DowField,
+			spec:     "* * * * *",
+			expected: time.Date(2022, 10, 7, 10, 6, 0, 0, time.UTC),
+		},
+
+		// optional day of week field, missing.
+		{
+			options:  MinuteField | HourField | DomField | MonthField | DowOptional,
+			spec:     "0 0 1 *",
+			expected: time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+
+		// fields not accepted take their defaults.
+		{
+			options:  MinuteField | HourField,
+			spec:     "15 *",
+			expected: time.Date(2022, 10, 7, 10, 15, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		sched, err := NewParser(c.options).Parse(c.spec)
+		if err != nil {
+			t.Fatalf("%q: %v", c.spec, err)
+		}
+
+		if got, want := sched.(*specSchedule).Next(now), c.expected; !got.Equal(want) {
+			t.Fatalf("%q: got: %v want: %v", c.spec, got, want)
+		}
+	}
+}
+
+func TestParserOptionsErrors(t *testing.T) {
+	cases := []struct {
+		options ParseOption
+		spec    string
+	}{
+		{SecondField | MinuteField | HourField | DomField | MonthField | DowField, "* * * * *"},
+		{SecondOptional | MinuteField | HourField | DomField | MonthField | DowField, "* * * *"},
+		{SecondOptional | MinuteField | HourField | DomField | MonthField | DowOptional, "* * * * *"},
+		{MinuteField | HourField, "* * *"},
+		{MinuteField | HourField, "@daily"},
+	}
+
+	for _, c := range cases {
+		if _, err := NewParser(c.options).Parse(c.spec); err == nil {
+			t.Fatalf("%q: expected error", c.spec)
+		}
+	}
+}
+
+func TestParseDescriptors(t *testing.T) {
+	now := time.Date(2022, 10, 7, 10, 5, 0, 0, time.UTC)
+
+	cases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"@yearly", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@annually", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2022, 10, 9, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2022, 10, 8, 0, 0, 0, 0, time.UTC)},
+		{"@midnight", time.Date(2022, 10, 8, 0, 0, 0, 0, time.UTC)},
+		{"@HOURLY", time.Date(2022, 10, 7, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2022, 10, 7, 10, 6, 30, 0, time.UTC)},
+		{"@every\t90s", time.Date(2022, 10, 7, 10, 6, 30, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		sched, err := Parse(c.spec)
+		if err != nil {
+			t.Fatalf("%q: %v", c.spec, err)
+		}
+		sched.(CyclicSchedule).MoveNextAvtivation(now)
+
+		if got, want := now.Add(sched.Calculate(now)), c.expected; !got.Equal(want) {
+			t.Fatalf("%q: got: %v want: %v", c.spec, got, want)
+		}
+	}
+
+	sched, err := Parse("@reboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sched.(*rebootSchedule); !ok {
+		t.Fatalf("got: %T want: %T", sched, &rebootSchedule{})
+	}
+
+	for _, spec := range []string{"@every", "@every 1x", "@reboot 5", "@daily 5", "@fortnightly"} {
+		if _, err := Parse(spec); err == nil {
+			t.Fatalf("%q: expected error", spec)
+		}
+	}
+}
+
+func TestHashRange(t *testing.T) {
+	cases := []struct {
+		expr     string
+		field    int
+		hash     uint64
+		expected string
+	}{
+		{"H", 1, 100, "40"},
+		{"H(8-11)", 2, 5, "9"},
+		{"H/15", 1, 100, "10-59/15"},
+		{"H(0-2)/5", 1, 100, "1-2/5"},
+		{"H(jan-mar)", 4, 4, "2"},
+
+		// day of month and day of week values exist in every month and week.
+		{"H", 3, 30, "3"},
+		{"H", 5, 13, "6"},
+	}
+
+	for _, c := range cases {
+		got, err := hashRange(c.expr, places[c.field].bounds, c.field, c.hash)
+		if err != nil {
+			t.Fatalf("%q: %v", c.expr, err)
+		}
+
+		if got != c.expected {
+			t.Fatalf("%q: got: %v want: %v", c.expr, got, c.expected)
+		}
+	}
+}
+
+func TestParseKeyed(t *testing.T) {
+	spec := "H H(8-11) * * H"
+
+	first, err := ParseKeyed(spec, "billing-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ParseKeyed(spec, "billing-report")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same key derives the same schedule.
+	if got, want := *first.(*specSchedule), *second.(*specSchedule); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %+v want: %+v", got, want)
+	}
+
+	sched := first.(*specSchedule)
+	if bits.OnesCount64(sched.minute) != 1 || bits.OnesCount64(sched.hour) != 1 || sched.hour&bitRange(8, 11, 1) == 0 {
+		t.Fatalf("got: minute: %b hour: %b", sched.minute, sched.hour)
+	}
+
+	// without H the key is ignored.
+	if _, err := ParseKeyed("0 9 * * *", "billing-report"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, spec := range []string{
+		"H(5-3) * * * *",
+		"H(0-60) * * * *",
+		"H(1-2 * * * *",
+		"H(1) * * * *",
+		"H/0 * * * *",
+		"H/5/5 * * * *",
+		"* * H(1-31) * *",
+		"Hx * * * *",
+	} {
+		if _, err := ParseKeyed(spec, "billing-report"); err == nil {
+			t.Fatalf("%q: expected error", spec)
+		}
+	}
+
+	if _, err := Parse("H * * * *"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	calendar := NewCalendar(time.Saturday, time.Sunday).AddHolidays(time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC))
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parser := NewParser(SecondOptional | MinuteField | HourField | DomField | MonthField | DowField | Descriptor)
+	seconds, err := parser.Parse("*/10 * * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []Schedule{
+		seconds,
+		spec("CRON_TZ=Europe/Paris 0 9 * * 1-5")(),
+		spec("0 0 L-2 * 5L")(),
+		Every(90 * time.Minute),
+		EveryFixed(3 * time.Hour),
+		EveryFixedFrom(time.Hour, start.Add(15*time.Minute)),
+		At(start),
+		InLocation(EveryFixed(time.Hour), newYork),
+		Solar(CivilDusk, 52.52, -13.405, -30*time.Minute),
+		Union(spec("0 9 * * *")(), spec("0 17 * * *")()),
+		Intersect(spec("*/15 * * * *")(), spec("* 9-16 * * 1-5")()),
+		Except(spec("*/15 * * * *")(), spec("* * 25 12 *")()),
+		Between(start, time.Time{}, Every(time.Hour)),
+		TimesFrom(3, start, spec("@daily")()),
+		ExponentialBackoff(time.Second, time.Minute, 1.5),
+		FibonacciBackoff(time.Second, time.Minute),
+		WithStableJitter(EveryFixed(time.Hour), 10*time.Minute, "billing report"),
+		OnBusinessDays(spec("0 9 * * *")(), calendar),
+		RollBackward(spec("0 9 1 * *")(), calendar),
+		WithDSTPolicy(spec("CRON_TZ=Europe/Berlin 30 2 * * *")(), DSTSkip),
+	}
+
+	for _, sched := range cases {
+		str := sched.(fmt.Stringer).String()
+
+		parsed, err := parser.Parse(str)
+		if err != nil {
+			t.Fatalf("%q: %v", str, err)
+		}
+
+		if got := parsed.(fmt.Stringer).String(); got != str {
+			t.Fatalf("got: %q want: %q", got, str)
+		}
+
+		if got, want := Preview(parsed, start, 3), Preview(sched, start, 3); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%q: got: %v want: %v", str, got, want)
+		}
+	}
+}
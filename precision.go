@@ -0,0 +1,48 @@
+package cronjob
+
+import (
+	"time"
+)
+
+// defaultPrecision is the shortest interval of the schedules of a cronjob.
+const defaultPrecision = time.Second
+
+// highPrecision is the shortest interval of the schedules of a cronjob in high precision
+// mode.
+const highPrecision = time.Millisecond
+
+// precisionSchedule is implemented by schedules with intervals and by the schedules
+// wrapping them.
+type precisionSchedule interface {
+	// withPrecision returns a copy of the schedule with its intervals under the precision
+	// raised to it, or the schedule itself and false if none of them had to be raised.
+	withPrecision(time.Duration) (Schedule, bool)
+}
+
+// withPrecision returns schedule (field) with its intervals under precision (field)
+// raised to it, reports whether they had to be raised.
+//
+// schedule (field) is never changed, it can be shared by other jobs and cronjobs.
+func withPrecision(schedule Schedule, precision time.Duration) (Schedule, bool) {
+	if sched, ok := schedule.(precisionSchedule); ok {
+		return sched.withPrecision(precision)
+	}
+
+	return schedule, false
+}
+
+// withPrecisions returns all of schedules (field) with their intervals under
+// precision (field) raised to it, reports whether any of them had to be raised.
+func withPrecisions(precision time.Duration, schedules ...Schedule) ([]Schedule, bool) {
+	var (
+		raised  = make([]Schedule, len(schedules))
+		changed bool
+	)
+	for i, sched := range schedules {
+		var ok bool
+		raised[i], ok = withPrecision(sched, precision)
+		changed = changed || ok
+	}
+
+	return raised, changed
+}
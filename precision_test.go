@@ -0,0 +1,145 @@
+package cronjob
+
+import (
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithPrecision(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		schedule  func() Schedule
+		precision time.Duration
+		expected  string
+	}{
+		{"in", func() Schedule { return In(now, 10*time.Millisecond) }, defaultPrecision, "2026-10-17T10:05:01Z"},
+		{"in high precision", func() Schedule { return In(now, 10*time.Millisecond) }, highPrecision, "2026-10-17T10:05:00.01Z"},
+		{"at", func() Schedule { return At(now.Add(10 * time.Millisecond)) }, defaultPrecision, "2026-10-17T10:05:00.01Z"},
+		{"every", func() Schedule { return Every(10 * time.Millisecond) }, defaultPrecision, "2026-10-17T10:05:01Z"},
+		{"every high precision", func() Schedule { return Every(10 * time.Millisecond) }, highPrecision, "2026-10-17T10:05:00.01Z"},
+		{"every fixed", func() Schedule { return EveryFixed(250 * time.Millisecond) }, defaultPrecision, "2026-10-17T10:05:01Z"},
+		{"every fixed high precision", func() Schedule { return EveryFixed(250 * time.Millisecond) }, highPrecision, "2026-10-17T10:05:00.25Z"},
+		{"wrapped", func() Schedule { return Union(Every(10*time.Millisecond), spec("0 12 * * *")()) }, defaultPrecision, "2026-10-17T10:05:01Z"},
+	}
+
+	for _, c := range cases {
+		sched, _ := withPrecision(c.schedule(), c.precision)
+
+		if got, want := nextRun(now, sched), mustParseTime(t, c.expected); !got.Equal(want) {
+			t.Fatalf("%v: got: %v want: %v", c.name, got, want)
+		}
+	}
+}
+
+func TestWithPrecisionShared(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+	shared := Union(Every(10*time.Millisecond), spec("0 12 * * *")())
+
+	// the schedule is raised for the cronjob without high precision only.
+	New().AddFunc(func() error { return nil }, shared)
+	New(WithHighPrecision()).AddFunc(func() error { return nil }, shared)
+
+	if got, want := nextRun(now, shared), now.Add(10*time.Millisecond); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// schedules which dont need to be raised arent copied.
+	sched := EveryFixed(time.Hour)
+	if got, _ := withPrecision(sched, defaultPrecision); got != sched {
+		t.Fatalf("got: %v want: %v", got, sched)
+	}
+}
+
+func TestHighPrecision(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		runs []time.Time
+		done = make(chan struct{})
+	)
+
+	c := New(WithHighPrecision(), WithLogger(log.New(io.Discard, "", 0)))
+	c.AddFunc(func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if runs = append(runs, time.Now()); len(runs) == 11 {
+			close(done)
+		}
+		return nil
+	}, Every(20*time.Millisecond))
+	c.Start()
+	defer c.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("got: less than 11 runs want: 11 runs")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var gaps []time.Duration
+	for i := 1; i < 11; i++ {
+		gaps = append(gaps, runs[i].Sub(runs[i-1]))
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+
+	// the runs are about 20ms apart, a second precision would space them by a second.
+	if median := gaps[len(gaps)/2]; median < 10*time.Millisecond || median > 500*time.Millisecond {
+		t.Fatalf("got: %v between runs want: about 20ms", median)
+	}
+}
+
+// BenchmarkHighPrecisionJitter reports how late the activations of a job running every
+// 5 milliseconds are, while 100 other jobs run at the same interval.
+func BenchmarkHighPrecisionJitter(b *testing.B) {
+	const interval = 5 * time.Millisecond
+
+	c := New(WithHighPrecision(), WithLogger(log.New(io.Discard, "", 0)))
+	for i := 0; i < 100; i++ {
+		c.AddFunc(func() error { return nil }, Every(interval))
+	}
+
+	var (
+		mu       sync.Mutex
+		lateness []time.Duration
+		wg       sync.WaitGroup
+	)
+	wg.Add(1)
+
+	anchor := time.Now()
+	c.AddFunc(func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if len(lateness) == b.N {
+			return nil
+		}
+
+		now := time.Now()
+		lateness = append(lateness, now.Sub(anchor)%interval)
+		if len(lateness) == b.N {
+			wg.Done()
+		}
+		return nil
+	}, EveryFixedFrom(interval, anchor))
+
+	b.ResetTimer()
+	c.Start()
+	wg.Wait()
+	c.Stop()
+	b.StopTimer()
+
+	sort.Slice(lateness, func(i, j int) bool { return lateness[i] < lateness[j] })
+	b.ReportMetric(float64(lateness[len(lateness)/2].Microseconds()), "p50-µs")
+	b.ReportMetric(float64(lateness[len(lateness)*99/100].Microseconds()), "p99-µs")
+}
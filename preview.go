@@ -0,0 +1,61 @@
+package cronjob
+
+import (
+	"time"
+)
+
+// Preview returns the first n (field) activations of schedule (field) after
+// from (field), without moving the schedule.
+//
+// example:
+//
+//	sched, _ := cronjob.Parse("0 9 * * 1-5")
+//	cronjob.Preview(sched, time.Now(), 5)
+//
+// returns the next 5 weekdays at 09:00.
+//
+// fewer activations are returned if the schedule runs out of them. the built-in
+// schedules are left untouched, custom schedules implementing CyclicSchedule are moved
+// to compute their activations. the Times schedules which werent added to a cronjob yet
+// count their activations from from (field).
+func Preview(schedule Schedule, from time.Time, n int) []time.Time {
+	schedule, _ = startTimes(schedule, from)
+
+	var activations []time.Time
+
+	t := from
+	for i := 0; i < n; i++ {
+		if t = nextAfter(schedule, t); t.IsZero() {
+			break
+		}
+
+		activations = append(activations, t)
+	}
+
+	return activations
+}
+
+// Prev returns the last activation of schedule (field) before from (field), without
+// moving the schedule.
+//
+// returns the zero time if there is none in the 8 years before from (field). the
+// schedules are expected to run at fixed times (Parse, EveryFixed, ...), Every has no
+// fixed times to look back at.
+func Prev(schedule Schedule, from time.Time) time.Time {
+	// look back in a window growing until it holds an activation.
+	for window := time.Second; window <= searchDays*day; window *= 2 {
+		var prev time.Time
+
+		t := nextFrom(schedule, from.Add(-window))
+		for steps := 0; steps < combineSteps && !t.IsZero() && t.Before(from); steps++ {
+			prev = t
+			t = nextAfter(schedule, t)
+		}
+
+		if !prev.IsZero() {
+			return prev
+		}
+	}
+
+	return time.Time{}
+}
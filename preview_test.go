@@ -0,0 +1,117 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreview(t *testing.T) {
+	// a saturday.
+	from := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		schedule Schedule
+		n        int
+		expected []string
+	}{
+		{
+			name:     "spec",
+			schedule: spec("0 9 * * 1-5")(),
+			n:        3,
+			expected: []string{"2026-10-19T09:00:00Z", "2026-10-20T09:00:00Z", "2026-10-21T09:00:00Z"},
+		},
+		{
+			name:     "every",
+			schedule: Every(time.Hour),
+			n:        2,
+			expected: []string{"2026-10-17T11:05:00Z", "2026-10-17T12:05:00Z"},
+		},
+		{
+			name:     "every fixed in location",
+			schedule: InLocation(EveryFixed(6*time.Hour), time.FixedZone("UTC+2", 2*60*60)),
+			n:        2,
+			expected: []string{"2026-10-17T18:00:00+02:00", "2026-10-18T00:00:00+02:00"},
+		},
+		{
+			name:     "at",
+			schedule: At(from.Add(time.Minute)),
+			n:        3,
+			expected: []string{"2026-10-17T10:06:00Z"},
+		},
+		{
+			name:     "at passed",
+			schedule: At(from),
+			n:        3,
+		},
+		{
+			name:     "times",
+			schedule: Times(2, spec("0 * * * *")()),
+			n:        3,
+			expected: []string{"2026-10-17T11:00:00Z", "2026-10-17T12:00:00Z"},
+		},
+		{
+			name:     "times in union",
+			schedule: Union(Times(2, spec("30 * * * *")()), spec("0 * * * *")()),
+			n:        4,
+			expected: []string{"2026-10-17T10:30:00Z", "2026-10-17T11:00:00Z", "2026-10-17T11:30:00Z", "2026-10-17T12:00:00Z"},
+		},
+		{
+			name:     "none",
+			schedule: spec("0 9 * * *")(),
+			n:        0,
+		},
+	}
+
+	for _, c := range cases {
+		got := Preview(c.schedule, from, c.n)
+		if len(got) != len(c.expected) {
+			t.Fatalf("%v: got: %v want: %v", c.name, got, c.expected)
+		}
+
+		for i, want := range c.expected {
+			if !got[i].Equal(mustParseTime(t, want)) {
+				t.Fatalf("%v: activation %v: got: %v want: %v", c.name, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestPreviewDoesntMove(t *testing.T) {
+	from := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	sched := Union(spec("0 9 * * *")(), EveryFixed(time.Hour))
+	want := activate(sched, from)
+
+	Preview(sched, from.Add(48*time.Hour), 10)
+	Prev(sched, from.Add(48*time.Hour))
+
+	if got := sched.Calculate(from); got != want.Sub(from) {
+		t.Fatalf("got: %v want: %v", got, want.Sub(from))
+	}
+}
+
+func TestPrev(t *testing.T) {
+	from := time.Date(2026, 10, 17, 10, 5, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		schedule Schedule
+		from     time.Time
+		expected string
+	}{
+		{"spec", spec("0 9 * * 1-5")(), from, "2026-10-16T09:00:00Z"},
+		{"spec on an activation", spec("0 9 * * *")(), time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), "2026-10-16T09:00:00Z"},
+		{"every fixed", EveryFixed(15 * time.Minute), from, "2026-10-17T10:00:00Z"},
+		{"yearly", spec("0 0 29 2 *")(), from, "2024-02-29T00:00:00Z"},
+		{"at", At(from.Add(-time.Hour)), from, "2026-10-17T09:05:00Z"},
+		{"at ahead", At(from.Add(time.Hour)), from, ""},
+		{"between", Between(time.Time{}, from.Add(-72*time.Hour), spec("0 9 * * *")()), from, "2026-10-14T09:00:00Z"},
+	}
+
+	for _, c := range cases {
+		if got, want := Prev(c.schedule, c.from), mustParseTime(t, c.expected); !got.Equal(want) {
+			t.Fatalf("%v: got: %v want: %v", c.name, got, want)
+		}
+	}
+}
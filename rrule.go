@@ -0,0 +1,638 @@
+package cronjob
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// frequency is the FREQ of a recurrence rule, ordered from the longest to the shortest.
+type frequency int
+
+const (
+	yearly frequency = iota
+	monthly
+	weekly
+	daily
+	hourly
+	minutely
+	secondly
+)
+
+var frequencies = map[string]frequency{
+	"YEARLY":   yearly,
+	"MONTHLY":  monthly,
+	"WEEKLY":   weekly,
+	"DAILY":    daily,
+	"HOURLY":   hourly,
+	"MINUTELY": minutely,
+	"SECONDLY": secondly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// rruleHorizon is how far ahead of the time searched from a recurrence rule looks for
+// occurrences before giving up.
+const rruleHorizon = 400
+
+// ParseRRule parses an iCalendar (RFC 5545) recurrence rule and returns a schedule which
+// runs on each occurrence of the rule.
+//
+// the rule can be given on its own, with the "RRULE:" prefix or after a "DTSTART" line
+// (separated by a newline or a space):
+//
+//	cronjob.ParseRRule("DTSTART;TZID=Europe/Paris:20260105T090000\nRRULE:FREQ=MONTHLY;BYDAY=MO,WE;BYSETPOS=-1;COUNT=10")
+//
+// runs at 09:00 paris time on the last monday or wednesday of the month, 10 times.
+//
+// the supported parts are FREQ, INTERVAL, COUNT, UNTIL, BYMONTH, BYMONTHDAY, BYDAY,
+// BYHOUR, BYMINUTE, BYSECOND, BYSETPOS and WKST. DTSTART is also accepted as a part
+// of the rule (DTSTART=20260105T090000Z).
+//
+// a DTSTART without a time zone (floating) and a rule without DTSTART are evaluated in
+// the location used by the cronjob. rules without DTSTART start at midnight of
+// 1970-01-01 (a thursday) and cant use COUNT.
+func ParseRRule(rule string) (Schedule, error) {
+	sched := &rruleSchedule{
+		interval: 1,
+		wkst:     time.Monday,
+		dtstart:  time.Unix(0, 0).UTC(),
+	}
+
+	var hasRule, hasStart bool
+	lines := strings.Fields(rule)
+	sched.source = strings.Join(lines, " ")
+
+	for _, line := range lines {
+		upper := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(upper, "DTSTART"):
+			if err := sched.parseStartLine(line); err != nil {
+				return nil, fmt.Errorf("cronjob: %q: %w", rule, err)
+			}
+			hasStart = true
+
+		case hasRule:
+			return nil, fmt.Errorf("cronjob: %q: more than one rule", rule)
+
+		default:
+			if strings.HasPrefix(upper, "RRULE:") {
+				line = line[len("RRULE:"):]
+			}
+
+			start, err := sched.parseRule(line)
+			if err != nil {
+				return nil, fmt.Errorf("cronjob: %q: %w", rule, err)
+			}
+			hasStart = hasStart || start
+			hasRule = true
+		}
+	}
+
+	switch {
+	case !hasRule:
+		return nil, fmt.Errorf("cronjob: %q: missing rule", rule)
+	case sched.count > 0 && !hasStart:
+		return nil, fmt.Errorf("cronjob: %q: COUNT requires DTSTART", rule)
+	}
+
+	return sched, nil
+}
+
+// RRuleSchedule ------------------------------------------------------------------
+
+// byDay is a BYDAY value: the n-th weekday, n is 0 for every weekday.
+type byDay struct {
+	n       int
+	weekday time.Weekday
+}
+
+type rruleSchedule struct {
+	freq     frequency
+	interval int
+
+	// count is the maximum number of occurrences, 0 if unbounded.
+	count int
+
+	// until is the last time an occurrence can be at, the zero time if unbounded.
+	until time.Time
+
+	// untilWall is set if until is a wall clock reading expressed in UTC rather than an
+	// instant.
+	untilWall bool
+
+	byMonth    []int
+	byMonthDay []int
+	byDay      []byDay
+	byHour     []int
+	byMinute   []int
+	bySecond   []int
+	bySetPos   []int
+	wkst       time.Weekday
+
+	// source is the rule the schedule was parsed from.
+	source string
+
+	// dtstart is the wall clock reading of the first occurrence, expressed in UTC.
+	dtstart time.Time
+
+	// location is the location of dtstart, if nil the location of the time passed to
+	// the schedule is used.
+	location *time.Location
+
+	// dst is the policy used for wall clock times skipped by daylight saving time.
+	dst DSTPolicy
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *rruleSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *rruleSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *rruleSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	if s.dst == policy {
+		return s, false
+	}
+
+	set := *s
+	set.dst = policy
+	return &set, true
+}
+
+// String returns the @rrule descriptor of the rule.
+func (s *rruleSchedule) String() string {
+	return withDST(s.dst, "@rrule "+s.source)
+}
+
+func (s *rruleSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *rruleSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *rruleSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first occurrence after: after (field).
+//
+// returns the zero time if the rule has no further occurrences.
+func (s *rruleSchedule) Next(after time.Time) time.Time {
+	if s.location != nil {
+		after = after.In(s.location)
+	}
+	loc := after.Location()
+	reading := wallReading(after)
+
+	// occurrences are counted from the start, otherwise skip to the period of after.
+	period := 0
+	if s.count == 0 {
+		if period = s.periodsUntil(reading) - 1; period < 0 {
+			period = 0
+		}
+	}
+
+	horizon := reading
+	if s.dtstart.After(horizon) {
+		horizon = s.dtstart
+	}
+	horizon = horizon.AddDate(rruleHorizon, 0, 0)
+
+	var occurrences int
+	for start := s.periodStart(period); !start.After(horizon); start = s.periodStart(period) {
+		// sub day periods of days which dont match can be skipped altogether.
+		if s.freq > daily && !s.matchDay(start) {
+			period += s.periodsUntil(start.Truncate(day).AddDate(0, 0, 1)) - period
+			continue
+		}
+		period++
+
+		for _, wall := range s.expand(start) {
+			if wall.Before(s.dtstart) {
+				continue
+			}
+			if s.untilWall && wall.After(s.until) {
+				return time.Time{}
+			}
+
+			t, ok := wallClock(wall, loc, s.dst)
+			if !s.untilWall && !s.until.IsZero() && t.After(s.until) {
+				return time.Time{}
+			}
+
+			if occurrences++; s.count > 0 && occurrences > s.count {
+				return time.Time{}
+			}
+			if ok && t.After(after) {
+				return t
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// periodStart returns the wall clock reading at which period (field) starts.
+func (s *rruleSchedule) periodStart(period int) time.Time {
+	n := period * s.interval
+	y, m, d := s.dtstart.Date()
+
+	switch s.freq {
+	case yearly:
+		return time.Date(y+n, 1, 1, 0, 0, 0, 0, time.UTC)
+	case monthly:
+		return time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	case weekly:
+		return s.weekStart(s.dtstart).AddDate(0, 0, 7*n)
+	case daily:
+		return time.Date(y, m, d+n, 0, 0, 0, 0, time.UTC)
+	case hourly:
+		return s.dtstart.Truncate(time.Hour).Add(time.Duration(n) * time.Hour)
+	case minutely:
+		return s.dtstart.Truncate(time.Minute).Add(time.Duration(n) * time.Minute)
+	default:
+		return s.dtstart.Truncate(time.Second).Add(time.Duration(n) * time.Second)
+	}
+}
+
+// periodsUntil returns the number of periods from the start of the rule to the period
+// which contains the wall clock reading: reading (field), rounded up.
+func (s *rruleSchedule) periodsUntil(reading time.Time) int {
+	var units int64
+	switch s.freq {
+	case yearly:
+		units = int64(reading.Year() - s.dtstart.Year())
+	case monthly:
+		units = int64(reading.Year()-s.dtstart.Year())*12 + int64(reading.Month()-s.dtstart.Month())
+	case weekly:
+		units = (s.weekStart(reading).Unix() - s.weekStart(s.dtstart).Unix()) / (7 * 86400)
+	case daily:
+		units = (reading.Truncate(day).Unix() - s.dtstart.Truncate(day).Unix()) / 86400
+	case hourly:
+		units = (reading.Unix() - s.dtstart.Truncate(time.Hour).Unix()) / 3600
+	case minutely:
+		units = (reading.Unix() - s.dtstart.Truncate(time.Minute).Unix()) / 60
+	default:
+		units = reading.Unix() - s.dtstart.Truncate(time.Second).Unix()
+	}
+
+	return int(-floorDiv(-units, int64(s.interval)))
+}
+
+// weekStart returns midnight of the first day of the week (WKST) of reading (field).
+func (s *rruleSchedule) weekStart(reading time.Time) time.Time {
+	offset := (int(reading.Weekday()) - int(s.wkst) + 7) % 7
+	return reading.Truncate(day).AddDate(0, 0, -offset)
+}
+
+// expand returns the sorted wall clock readings of the occurrences of the period starting
+// at start (field), before they are bounded by DTSTART, UNTIL and COUNT.
+func (s *rruleSchedule) expand(start time.Time) []time.Time {
+	var first, last time.Time
+	switch s.freq {
+	case yearly:
+		first, last = start, start.AddDate(1, 0, -1)
+	case monthly:
+		first, last = start, start.AddDate(0, 1, -1)
+	case weekly:
+		first, last = start, start.AddDate(0, 0, 6)
+	default:
+		first = start.Truncate(day)
+		last = first
+	}
+
+	hours := s.expandTime(s.byHour, s.dtstart.Hour(), hourly, start.Hour())
+	minutes := s.expandTime(s.byMinute, s.dtstart.Minute(), minutely, start.Minute())
+	seconds := s.expandTime(s.bySecond, s.dtstart.Second(), secondly, start.Second())
+
+	var set []time.Time
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if !s.matchDay(d) {
+			continue
+		}
+
+		for _, h := range hours {
+			for _, m := range minutes {
+				for _, sc := range seconds {
+					set = append(set, time.Date(d.Year(), d.Month(), d.Day(), h, m, sc, 0, time.UTC))
+				}
+			}
+		}
+	}
+
+	if len(s.bySetPos) == 0 {
+		return set
+	}
+
+	var positioned []time.Time
+	for i := range set {
+		for _, pos := range s.bySetPos {
+			if pos == i+1 || pos == i-len(set) {
+				positioned = append(positioned, set[i])
+				break
+			}
+		}
+	}
+	return positioned
+}
+
+// expandTime returns the values of a time part of the period.
+//
+// frequencies longer than freq (field) expand the part to the BY values or the value of
+// DTSTART: dtstart (field), the others limit the value of the period: value (field) to
+// the BY values.
+func (s *rruleSchedule) expandTime(by []int, dtstart int, freq frequency, value int) []int {
+	if s.freq < freq {
+		if len(by) == 0 {
+			return []int{dtstart}
+		}
+		return by
+	}
+
+	if len(by) == 0 || containsInt(by, value) {
+		return []int{value}
+	}
+	return nil
+}
+
+// matchDay reports whether the day: date (field) matches the BYMONTH, BYMONTHDAY and
+// BYDAY parts of the rule, or the day of DTSTART when they cant determine it.
+func (s *rruleSchedule) matchDay(date time.Time) bool {
+	if len(s.byMonth) > 0 && !containsInt(s.byMonth, int(date.Month())) {
+		return false
+	}
+
+	last := daysIn(date.Year(), date.Month())
+	if len(s.byMonthDay) > 0 {
+		var match bool
+		for _, d := range s.byMonthDay {
+			if d == date.Day() || d < 0 && last+d+1 == date.Day() {
+				match = true
+				break
+			}
+		}
+
+		if !match {
+			return false
+		}
+	}
+
+	if len(s.byDay) > 0 {
+		// ordinals are within the year for yearly rules without BYMONTH, within the month
+		// otherwise.
+		index, count := date.Day(), last
+		if s.freq == yearly && len(s.byMonth) == 0 {
+			index, count = date.YearDay(), time.Date(date.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		}
+
+		for _, d := range s.byDay {
+			if d.weekday != date.Weekday() {
+				continue
+			}
+
+			if d.n == 0 || d.n > 0 && (index-1)/7+1 == d.n || d.n < 0 && (count-index)/7+1 == -d.n {
+				return true
+			}
+		}
+		return false
+	}
+
+	// without day parts the day of DTSTART is used.
+	if len(s.byMonthDay) == 0 {
+		switch s.freq {
+		case yearly:
+			if len(s.byMonth) == 0 && date.Month() != s.dtstart.Month() {
+				return false
+			}
+			return date.Day() == s.dtstart.Day()
+		case monthly:
+			return date.Day() == s.dtstart.Day()
+		case weekly:
+			return date.Weekday() == s.dtstart.Weekday()
+		}
+	}
+
+	return true
+}
+
+// parseStartLine parses a DTSTART line.
+func (s *rruleSchedule) parseStartLine(line string) error {
+	i := strings.Index(line, ":")
+	if i == -1 {
+		return fmt.Errorf("missing value: %q", line)
+	}
+
+	var loc *time.Location
+	for _, param := range strings.Split(line[:i], ";")[1:] {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid parameter: %q", param)
+		}
+
+		if strings.ToUpper(kv[0]) == "TZID" {
+			var err error
+			if loc, err = time.LoadLocation(kv[1]); err != nil {
+				return fmt.Errorf("failed to load location %q: %w", kv[1], err)
+			}
+		}
+	}
+
+	return s.setStart(line[i+1:], loc)
+}
+
+// setStart sets DTSTART from its value: value (field) in loc (field).
+func (s *rruleSchedule) setStart(value string, loc *time.Location) error {
+	t, utc, err := parseRRuleTime(value)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case utc:
+		s.location = time.UTC
+	case loc != nil:
+		s.location = loc
+	}
+
+	s.dtstart = t
+	return nil
+}
+
+// parseRule parses the parts of the rule, reporting whether it contains DTSTART.
+func (s *rruleSchedule) parseRule(rule string) (bool, error) {
+	var hasFreq, hasStart bool
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return false, fmt.Errorf("invalid part: %q", part)
+		}
+		name, value := strings.ToUpper(kv[0]), kv[1]
+
+		var err error
+		switch name {
+		case "FREQ":
+			s.freq, hasFreq = frequencies[strings.ToUpper(value)]
+			if !hasFreq {
+				return false, fmt.Errorf("invalid frequency: %q", value)
+			}
+
+		case "INTERVAL":
+			if s.interval, err = strconv.Atoi(value); err != nil || s.interval < 1 {
+				return false, fmt.Errorf("interval should be a positive number: %q", value)
+			}
+
+		case "COUNT":
+			if s.count, err = strconv.Atoi(value); err != nil || s.count < 1 {
+				return false, fmt.Errorf("count should be a positive number: %q", value)
+			}
+
+		case "UNTIL":
+			var utc bool
+			if s.until, utc, err = parseRRuleTime(value); err != nil {
+				return false, err
+			}
+			s.untilWall = !utc
+
+		case "DTSTART":
+			if err = s.setStart(value, nil); err != nil {
+				return false, err
+			}
+			hasStart = true
+
+		case "BYMONTH":
+			s.byMonth, err = parseInts(value, 1, 12, false)
+		case "BYMONTHDAY":
+			s.byMonthDay, err = parseInts(value, 1, 31, true)
+		case "BYHOUR":
+			s.byHour, err = parseInts(value, 0, 23, false)
+		case "BYMINUTE":
+			s.byMinute, err = parseInts(value, 0, 59, false)
+		case "BYSECOND":
+			s.bySecond, err = parseInts(value, 0, 59, false)
+		case "BYSETPOS":
+			s.bySetPos, err = parseInts(value, 1, 366, true)
+		case "BYDAY":
+			s.byDay, err = parseByDay(value)
+
+		case "WKST":
+			var ok bool
+			if s.wkst, ok = weekdays[strings.ToUpper(value)]; !ok {
+				return false, fmt.Errorf("invalid weekday: %q", value)
+			}
+
+		default:
+			return false, fmt.Errorf("unsupported part: %q", name)
+		}
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !hasFreq {
+		return false, fmt.Errorf("missing FREQ")
+	}
+	if s.count > 0 && !s.until.IsZero() {
+		return false, fmt.Errorf("COUNT and UNTIL cant be used together")
+	}
+
+	return hasStart, nil
+}
+
+// parseRRuleTime parses an iCalendar date or date-time, reporting whether it is in UTC.
+func parseRRuleTime(value string) (time.Time, bool, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, strings.HasSuffix(layout, "Z"), nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid date-time: %q", value)
+}
+
+// parseInts parses a sorted list of comma separated numbers between min (field) and max
+// (field), or between -max and -min if negative (field) is set.
+func parseInts(value string, min, max int, negative bool) ([]int, error) {
+	var ints []int
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse number %q", v)
+		}
+
+		abs := n
+		if negative && n < 0 {
+			abs = -n
+		}
+		if abs < min || abs > max {
+			return nil, fmt.Errorf("number (%v) out of range: %q", n, value)
+		}
+
+		ints = append(ints, n)
+	}
+
+	sort.Ints(ints)
+	return ints, nil
+}
+
+// parseByDay parses a comma separated list of weekdays, optionally preceded by their
+// ordinal (1MO, -1FR).
+func parseByDay(value string) ([]byDay, error) {
+	var days []byDay
+	for _, v := range strings.Split(value, ",") {
+		v = strings.ToUpper(v)
+		if len(v) < 2 {
+			return nil, fmt.Errorf("invalid weekday: %q", v)
+		}
+
+		weekday, ok := weekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday: %q", v)
+		}
+
+		var n int
+		if ordinal := v[:len(v)-2]; ordinal != "" {
+			var err error
+			if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday ordinal: %q", v)
+			}
+		}
+
+		days = append(days, byDay{n, weekday})
+	}
+
+	return days, nil
+}
+
+func containsInt(ints []int, v int) bool {
+	for _, i := range ints {
+		if i == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,265 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRRule(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// examples from RFC 5545, section 3.8.5.3.
+	const dtstart = "DTSTART;TZID=America/New_York:19970902T090000\n"
+
+	cases := []struct {
+		rule     string
+		expected []string
+	}{
+		{
+			rule: dtstart + "RRULE:FREQ=DAILY;COUNT=10",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-03 09:00", "1997-09-04 09:00", "1997-09-05 09:00",
+				"1997-09-06 09:00", "1997-09-07 09:00", "1997-09-08 09:00", "1997-09-09 09:00",
+				"1997-09-10 09:00", "1997-09-11 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=DAILY;UNTIL=19970905T000000Z",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-03 09:00", "1997-09-04 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=DAILY;INTERVAL=10;COUNT=5",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-12 09:00", "1997-09-22 09:00", "1997-10-02 09:00",
+				"1997-10-12 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=WEEKLY;UNTIL=19971007T000000Z;WKST=SU;BYDAY=TU,TH",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-09 09:00", "1997-09-11 09:00",
+				"1997-09-16 09:00", "1997-09-18 09:00", "1997-09-23 09:00", "1997-09-25 09:00",
+				"1997-09-30 09:00", "1997-10-02 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=8;WKST=SU;BYDAY=TU,TH",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-16 09:00", "1997-09-18 09:00",
+				"1997-09-30 09:00", "1997-10-02 09:00", "1997-10-14 09:00", "1997-10-16 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MONTHLY;COUNT=6;BYDAY=1FR",
+			expected: []string{
+				"1997-09-05 09:00", "1997-10-03 09:00", "1997-11-07 09:00", "1997-12-05 09:00",
+				"1998-01-02 09:00", "1998-02-06 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			expected: []string{
+				"1997-09-22 09:00", "1997-10-20 09:00", "1997-11-17 09:00", "1997-12-22 09:00",
+				"1998-01-19 09:00", "1998-02-16 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MONTHLY;COUNT=6;BYMONTHDAY=2,15",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-15 09:00", "1997-10-02 09:00", "1997-10-15 09:00",
+				"1997-11-02 09:00", "1997-11-15 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MONTHLY;COUNT=6;BYMONTHDAY=-3",
+			expected: []string{
+				"1997-09-28 09:00", "1997-10-29 09:00", "1997-11-28 09:00", "1997-12-29 09:00",
+				"1998-01-29 09:00", "1998-02-26 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-2;COUNT=4",
+			expected: []string{
+				"1997-09-29 09:00", "1997-10-30 09:00", "1997-11-27 09:00", "1997-12-30 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MONTHLY;BYDAY=MO,WE;BYSETPOS=-1;COUNT=4",
+			expected: []string{
+				"1997-09-29 09:00", "1997-10-29 09:00", "1997-11-26 09:00", "1997-12-31 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=3",
+			expected: []string{
+				"1998-02-13 09:00", "1998-03-13 09:00", "1998-11-13 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=YEARLY;COUNT=6;BYMONTH=6,7",
+			expected: []string{
+				"1998-06-02 09:00", "1998-07-02 09:00", "1999-06-02 09:00", "1999-07-02 09:00",
+				"2000-06-02 09:00", "2000-07-02 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=YEARLY;BYDAY=20MO;COUNT=3",
+			expected: []string{
+				"1998-05-18 09:00", "1999-05-17 09:00", "2000-05-15 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=TH;COUNT=6",
+			expected: []string{
+				"1998-03-05 09:00", "1998-03-12 09:00", "1998-03-19 09:00", "1998-03-26 09:00",
+				"1999-03-04 09:00", "1999-03-11 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=HOURLY;INTERVAL=3;UNTIL=19970902T210000Z",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-02 12:00", "1997-09-02 15:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MINUTELY;INTERVAL=15;COUNT=6",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-02 09:15", "1997-09-02 09:30", "1997-09-02 09:45",
+				"1997-09-02 10:00", "1997-09-02 10:15",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=DAILY;BYHOUR=9,10;BYMINUTE=0,20,40;COUNT=8",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-02 09:20", "1997-09-02 09:40", "1997-09-02 10:00",
+				"1997-09-02 10:20", "1997-09-02 10:40", "1997-09-03 09:00", "1997-09-03 09:20",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10;COUNT=8",
+			expected: []string{
+				"1997-09-02 09:00", "1997-09-02 09:20", "1997-09-02 09:40", "1997-09-02 10:00",
+				"1997-09-02 10:20", "1997-09-02 10:40", "1997-09-03 09:00", "1997-09-03 09:20",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=MONTHLY;BYMONTHDAY=31;COUNT=4",
+			expected: []string{
+				"1997-10-31 09:00", "1997-12-31 09:00", "1998-01-31 09:00", "1998-03-31 09:00",
+			},
+		},
+		{
+			rule: dtstart + "RRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29;COUNT=2",
+			expected: []string{
+				"2000-02-29 09:00", "2004-02-29 09:00",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		sched, err := ParseRRule(c.rule)
+		if err != nil {
+			t.Fatalf("%q: %v", c.rule, err)
+		}
+
+		got := occurrences(sched.(*rruleSchedule), time.Date(1997, 1, 1, 0, 0, 0, 0, time.UTC), len(c.expected)+1)
+		if len(got) != len(c.expected) {
+			t.Fatalf("%q: got: %v want: %v", c.rule, got, c.expected)
+		}
+
+		for i := range got {
+			want, err := time.ParseInLocation("2006-01-02 15:04", c.expected[i], newYork)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !got[i].Equal(want) {
+				t.Fatalf("%q: occurrence %v: got: %v want: %v", c.rule, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestRRuleUnbounded(t *testing.T) {
+	cases := []struct {
+		rule     string
+		after    string
+		expected string
+	}{
+		// skips ahead to the period of after.
+		{"DTSTART:19970902T090000Z\nRRULE:FREQ=DAILY;INTERVAL=2", "2022-10-07T10:05:00Z", "2022-10-09T09:00:00Z"},
+		{"RRULE:FREQ=WEEKLY;BYDAY=MO;DTSTART=20220103T090000Z", "2022-10-07T10:05:00Z", "2022-10-10T09:00:00Z"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=18;BYMINUTE=30", "2022-02-07T10:05:00Z", "2022-02-28T18:30:00Z"},
+
+		// without DTSTART rules start at midnight of 1970-01-01.
+		{"FREQ=DAILY", "2022-10-07T10:05:00Z", "2022-10-08T00:00:00Z"},
+		{"FREQ=HOURLY;INTERVAL=5", "2022-10-07T10:05:00Z", "2022-10-07T12:00:00Z"},
+
+		// floating start times are evaluated in the location of after.
+		{"DTSTART:20220101T090000\nRRULE:FREQ=DAILY", "2022-10-07T10:05:00+02:00", "2022-10-08T09:00:00+02:00"},
+
+		// no further occurrences.
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", "2022-10-07T10:05:00Z", ""},
+		{"DTSTART:20220101T090000Z\nRRULE:FREQ=DAILY;COUNT=3", "2022-10-07T10:05:00Z", ""},
+	}
+
+	for _, c := range cases {
+		sched, err := ParseRRule(c.rule)
+		if err != nil {
+			t.Fatalf("%q: %v", c.rule, err)
+		}
+
+		got := sched.(*rruleSchedule).Next(mustParseTime(t, c.after))
+		if want := mustParseTime(t, c.expected); !got.Equal(want) {
+			t.Fatalf("%q: got: %v want: %v", c.rule, got, want)
+		}
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"DTSTART:20220101T090000Z",
+		"INTERVAL=2",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=5",
+		"FREQ=DAILY;BYMONTH=13",
+		"FREQ=DAILY;BYMONTHDAY=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=DAILY;BYDAY=0MO",
+		"FREQ=DAILY;BYHOUR=24",
+		"FREQ=DAILY;BYWEEKNO=20",
+		"FREQ=DAILY;UNTIL=2022",
+		"FREQ=DAILY;INTERVAL",
+		"DTSTART:20220101T090000Z\nRRULE:FREQ=DAILY;COUNT=2;UNTIL=20230101T000000Z",
+		"DTSTART;TZID=Mars/Olympus:20220101T090000\nRRULE:FREQ=DAILY",
+		"FREQ=DAILY\nFREQ=WEEKLY",
+	} {
+		if _, err := ParseRRule(rule); err == nil {
+			t.Fatalf("%q: expected error", rule)
+		}
+	}
+}
+
+// occurrences returns up to n occurrences of the rule after: after (field).
+func occurrences(sched *rruleSchedule, after time.Time, n int) []time.Time {
+	var times []time.Time
+	for i := 0; i < n; i++ {
+		next := sched.Next(after)
+		if next.IsZero() {
+			break
+		}
+
+		times = append(times, next)
+		after = next
+	}
+
+	return times
+}
@@ -1,9 +1,15 @@
 package cronjob
 
 import (
+	"math"
+	"math/big"
 	"time"
 )
 
+// never is the duration reported by Calculate when a schedule has no further
+// activations.
+const never time.Duration = math.MaxInt64
+
 // At returns a schedule that runs at: at (field).
 func At(at time.Time) Schedule {
 	return &constantSchedule{
@@ -12,20 +18,27 @@ func At(at time.Time) Schedule {
 }
 
 // In returns a schedule that runs from now (field) in offset (field).
+//
+// offsets under the precision of the cronjob (a second, see WithHighPrecision) are
+// raised to it.
 func In(now time.Time, offset time.Duration) Schedule {
-	if offset < time.Second {
-		offset = time.Second
+	if offset < time.Millisecond {
+		offset = time.Millisecond
 	}
 
 	return &constantSchedule{
-		at: now.Add(offset),
+		at:   now.Add(offset),
+		from: now,
 	}
 }
 
 // Every runs from now: now (field) in constant increments of every (field).
+//
+// increments under the precision of the cronjob (a second, see WithHighPrecision) are
+// raised to it.
 func Every(every time.Duration) Schedule {
-	if every < time.Second {
-		every = time.Second
+	if every < time.Millisecond {
+		every = time.Millisecond
 	}
 
 	return &cyclicSchedule{
@@ -36,17 +49,78 @@ func Every(every time.Duration) Schedule {
 // EveryFixed finds the next time interval: every (field) and runs it at the nearest interval.
 //
 // example:
+//
 //	cronjob.EveryFixed(time.Hour * 3)
+//
 // the schedule will find the next 3 hour interval to run at.
 //
 // possible 3 hour intervals: 03:00, 06:00, 09:00, 12:00, 15:00, 18:00, 21:00, 24:00
+//
+// intervals which divide a day are aligned to midnight, intervals of whole days are
+// aligned to midnight of 1970-01-01 (a thursday) and any other interval is aligned to
+// the unix epoch. use EveryFixedFrom to align the intervals to another time.
+//
+// intervals skipped or repeated by daylight saving time are handled according to the
+// policy of the schedule, see WithDSTPolicy.
 func EveryFixed(every time.Duration) Schedule {
-	if every < time.Second {
-		every = time.Second
+	return EveryFixedFrom(every, time.Time{})
+}
+
+// EveryFixedFrom finds the next time interval: every (field) aligned to anchor (field) and
+// runs it at the nearest interval.
+//
+// intervals which divide a day run each day at the time of day of anchor (field) and
+// its multiples of every (field), intervals of whole days run at the time of day of
+// anchor (field) on the days which are multiples of every (field) away from its date.
+// both use the wall clock reading of anchor (field).
+//
+// example:
+//
+//	cronjob.EveryFixedFrom(time.Hour * 24 * 14, time.Date(2022, 10, 3, 9, 0, 0, 0, time.Local))
+//
+// the schedule will run every other monday at 09:00.
+//
+// any other interval runs at the instants which are multiples of every (field) away
+// from anchor (field).
+//
+// intervals under the precision of the cronjob (a second, see WithHighPrecision) are
+// raised to it.
+func EveryFixedFrom(every time.Duration, anchor time.Time) Schedule {
+	if every < time.Millisecond {
+		every = time.Millisecond
 	}
 
 	return &fixedCyclicSchedule{
-		every: every,
+		every:  every,
+		anchor: anchor,
+	}
+}
+
+// InLocation returns a schedule which evaluates schedule (field) in loc (field) instead
+// of the location used by the cronjob.
+//
+// example:
+//
+//	cronjob.InLocation(cronjob.EveryFixed(time.Hour * 3), tokyo)
+//
+// the schedule will run at the 3 hour intervals of tokyo.
+//
+// a nil loc (field) returns schedule (field) unchanged.
+func InLocation(schedule Schedule, loc *time.Location) Schedule {
+	if loc == nil {
+		return schedule
+	}
+
+	if sched, ok := schedule.(CyclicSchedule); ok {
+		return &cyclicLocationSchedule{
+			schedule: sched,
+			location: loc,
+		}
+	}
+
+	return &locationSchedule{
+		schedule: schedule,
+		location: loc,
 	}
 }
 
@@ -55,6 +129,9 @@ func EveryFixed(every time.Duration) Schedule {
 type constantSchedule struct {
 	// is the date on which the job is scheduled to run on.
 	at time.Time
+
+	// from is the time the offset of In is counted from, the zero time for At.
+	from time.Time
 }
 
 // Calculate calculates the duration of time in which the schedule will be active
@@ -63,11 +140,118 @@ func (s *constantSchedule) Calculate(now time.Time) time.Duration {
 	return s.at.Sub(now)
 }
 
+// String returns the @at descriptor of the schedule.
+func (s *constantSchedule) String() string {
+	return "@at " + formatTime(s.at)
+}
+
+func (s *constantSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *constantSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *constantSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the date of the schedule if it is after: after (field).
+func (s *constantSchedule) Next(after time.Time) time.Time {
+	if !s.at.After(after) {
+		return time.Time{}
+	}
+
+	return s.at
+}
+
+// withPrecision raises the offset of In to precision (field).
+func (s *constantSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	if s.from.IsZero() || s.at.Sub(s.from) >= precision {
+		return s, false
+	}
+
+	return &constantSchedule{at: s.from.Add(precision), from: s.from}, true
+}
+
+// RebootSchedule ------------------------------------------------------------------
+
+// rebootSchedule runs the job once when the cronjob starts, it is handled by the
+// cronjob the same way as WithRunOnStart.
+type rebootSchedule struct{}
+
+// Calculate always reports never, the activation is handled when adding the job.
+func (s *rebootSchedule) Calculate(now time.Time) time.Duration {
+	return never
+}
+
+func (s *rebootSchedule) String() string {
+	return "@reboot"
+}
+
+func (s *rebootSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *rebootSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *rebootSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next always returns the zero time, the activation isnt a time.
+func (s *rebootSchedule) Next(after time.Time) time.Time {
+	return time.Time{}
+}
+
+// rebootOf reports whether schedule (field) runs when the cronjob starts, being @reboot
+// or holding it in InLocation or Union, and whether that is its only activation.
+func rebootOf(schedule Schedule) (reboot, only bool) {
+	switch s := schedule.(type) {
+	case *rebootSchedule:
+		return true, true
+
+	case *locationSchedule:
+		return rebootOf(s.schedule)
+
+	case *cyclicLocationSchedule:
+		return rebootOf(s.schedule)
+
+	case *nextSchedule:
+		if sched, ok := s.schedule.(Schedule); ok {
+			return rebootOf(sched)
+		}
+
+	case *unionSchedule:
+		only = true
+		for _, sched := range s.schedules {
+			r, o := rebootOf(sched)
+			reboot, only = reboot || r, only && o
+		}
+		return reboot, reboot && only
+	}
+
+	return false, false
+}
+
 // FixedCyclicSchedule ------------------------------------------------------------------
 
+// day is the length of a day without daylight saving time transitions.
+const day = 24 * time.Hour
+
 type fixedCyclicSchedule struct {
 	every time.Duration
 
+	// anchor is the time the intervals are aligned to, the zero time aligns them to
+	// midnight or the unix epoch.
+	anchor time.Time
+
+	// dst is the policy used for wall clock times skipped by daylight saving time.
+	dst DSTPolicy
+
 	nextActivation time.Time
 }
 
@@ -76,58 +260,162 @@ func (s *fixedCyclicSchedule) Calculate(now time.Time) time.Duration {
 }
 
 func (s *fixedCyclicSchedule) MoveNextAvtivation(now time.Time) {
-	s.nextActivation = s.calculateNextInterval(now)
-}
-
-func (s *fixedCyclicSchedule) calculateNextInterval(now time.Time) time.Time {
-	switch h, m, s := breakTime(int(s.every.Seconds())); {
-	case h > 0:
-		return time.Date(
-			now.Year(),
-			now.Month(),
-			now.Day(),
-			h*(now.Hour()/h+1),
-			m,
-			s,
-			0,
-			now.Location(),
-		)
-
-	case m > 0:
-		return time.Date(
-			now.Year(),
-			now.Month(),
-			now.Day(),
-			now.Hour(),
-			m*(now.Minute()/m+1),
-			s,
-			0,
-			now.Location(),
-		)
+	s.nextActivation = s.Next(now)
+}
+
+func (s *fixedCyclicSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	if s.dst == policy {
+		return s, false
+	}
+
+	set := *s
+	set.dst = policy
+	return &set, true
+}
+
+func (s *fixedCyclicSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	if s.every >= precision {
+		return s, false
+	}
+
+	raised := *s
+	raised.every = precision
+	return &raised, true
+}
+
+// String returns the @every-fixed descriptor of the schedule, followed by its anchor if
+// it has one.
+func (s *fixedCyclicSchedule) String() string {
+	spec := "@every-fixed " + s.every.String()
+	if !s.anchor.IsZero() {
+		spec += " " + formatTime(s.anchor)
+	}
 
+	return withDST(s.dst, spec)
+}
+
+func (s *fixedCyclicSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *fixedCyclicSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *fixedCyclicSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first interval after: after (field) in the location of after (field).
+//
+// the interval returned is always after after (field), so consecutive intervals are
+// monotonically increasing.
+func (s *fixedCyclicSchedule) Next(after time.Time) time.Time {
+	switch {
+	case s.every%day == 0:
+		return s.nextDays(after)
+	case day%s.every == 0:
+		return s.nextOfDay(after)
 	default:
-		return time.Date(
-			now.Year(),
-			now.Month(),
-			now.Day(),
-			now.Hour(),
-			now.Minute(),
-			s*(now.Second()/s+1),
-			0,
-			now.Location(),
-		)
+		return s.nextInstant(after)
+	}
+}
+
+// nextOfDay returns the first interval after: after (field) for intervals which divide a
+// day.
+func (s *fixedCyclicSchedule) nextOfDay(after time.Time) time.Time {
+	// the time of day the intervals are shifted by.
+	offset := timeOfDay(wallReading(s.anchor)) % s.every
+
+	// intervals are wall clock readings, which daylight saving time can skip or repeat.
+	// readings before the reading of after (field) resolve to instants before it.
+	reading := wallReading(after)
+	midnight := reading.Truncate(day)
+
+	k := (reading.Sub(midnight) - offset) / s.every
+	if reading.Sub(midnight) >= offset {
+		k++
+	}
+
+	for {
+		for ; offset+k*s.every < day; k++ {
+			wall := midnight.Add(offset + k*s.every)
+			if t, ok := wallClock(wall, after.Location(), s.dst); ok && t.After(after) {
+				return t
+			}
+		}
+
+		midnight = midnight.AddDate(0, 0, 1)
+		k = 0
+	}
+}
+
+// nextDays returns the first interval after: after (field) for intervals of whole days.
+func (s *fixedCyclicSchedule) nextDays(after time.Time) time.Time {
+	anchor := wallReading(s.anchor)
+	if s.anchor.IsZero() {
+		anchor = time.Unix(0, 0).UTC()
+	}
+	anchorDate := anchor.Truncate(day)
+	offset := anchor.Sub(anchorDate)
+
+	// start from the interval on or before the day of after (field).
+	days := int64(s.every / day)
+	elapsed := (wallReading(after).Truncate(day).Unix() - anchorDate.Unix()) / 86400
+	k := floorDiv(elapsed, days)
+
+	for ; ; k++ {
+		wall := anchorDate.AddDate(0, 0, int(k*days)).Add(offset)
+		if t, ok := wallClock(wall, after.Location(), s.dst); ok && t.After(after) {
+			return t
+		}
 	}
 }
 
-func breakTime(secs int) (hour, minute, second int) {
-	hour = secs / 3600
-	secs = secs - hour*3600
+// nextInstant returns the first interval after: after (field) for any other interval.
+func (s *fixedCyclicSchedule) nextInstant(after time.Time) time.Time {
+	anchor := s.anchor
+	if anchor.IsZero() {
+		anchor = time.Unix(0, 0)
+	}
+
+	// the time elapsed since the anchor is counted in whole seconds and nanoseconds, a
+	// duration cant hold more than 292 years.
+	elapsed := new(big.Int).Mul(big.NewInt(after.Unix()-anchor.Unix()), big.NewInt(int64(time.Second)))
+	elapsed.Add(elapsed, big.NewInt(int64(after.Nanosecond()-anchor.Nanosecond())))
+
+	// the time elapsed since the last interval, not negative.
+	since := time.Duration(elapsed.Mod(elapsed, big.NewInt(int64(s.every))).Int64())
+	return after.Add(s.every - since)
+}
+
+// wallReading returns the wall clock reading of t (field) expressed in UTC.
+func wallReading(t time.Time) time.Time {
+	return time.Date(
+		t.Year(),
+		t.Month(),
+		t.Day(),
+		t.Hour(),
+		t.Minute(),
+		t.Second(),
+		t.Nanosecond(),
+		time.UTC,
+	)
+}
 
-	minute = secs / 60
-	secs = secs - minute*60
+// timeOfDay returns the time elapsed since midnight of t (field), which must be in UTC.
+func timeOfDay(t time.Time) time.Duration {
+	return t.Sub(t.Truncate(day))
+}
+
+// floorDiv returns a / b rounded towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
 
-	second = secs
-	return
+	return q
 }
 
 // CyclicSchedule ------------------------------------------------------------------
@@ -145,5 +433,169 @@ func (s *cyclicSchedule) Calculate(now time.Time) time.Duration {
 }
 
 func (s *cyclicSchedule) MoveNextAvtivation(now time.Time) {
-	s.nextActivation = now.Add(s.every)
+	s.nextActivation = s.Next(now)
+}
+
+// String returns the @every descriptor of the schedule.
+func (s *cyclicSchedule) String() string {
+	return "@every " + s.every.String()
+}
+
+func (s *cyclicSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *cyclicSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *cyclicSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the activation following after: after (field).
+func (s *cyclicSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.every)
+}
+
+func (s *cyclicSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	if s.every >= precision {
+		return s, false
+	}
+
+	return &cyclicSchedule{every: precision}, true
+}
+
+// LocationSchedule ------------------------------------------------------------------
+
+type locationSchedule struct {
+	schedule Schedule
+
+	// location is the location the schedule is evaluated in.
+	location *time.Location
+}
+
+func (s *locationSchedule) Calculate(now time.Time) time.Duration {
+	return s.schedule.Calculate(now.In(s.location))
+}
+
+func (s *locationSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	sched, ok := withDSTPolicy(s.schedule, policy)
+	if !ok {
+		return s, false
+	}
+
+	set := *s
+	set.schedule = sched
+	return &set, true
+}
+
+func (s *locationSchedule) startTimes(start time.Time) (Schedule, bool) {
+	sched, ok := startTimes(s.schedule, start)
+	if !ok {
+		return s, false
+	}
+
+	started := *s
+	started.schedule = sched
+	return &started, true
+}
+
+func (s *locationSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	sched, ok := withPrecision(s.schedule, precision)
+	if !ok {
+		return s, false
+	}
+
+	raised := *s
+	raised.schedule = sched
+	return &raised, true
+}
+
+// String returns the spec of the schedule prefixed with the location.
+func (s *locationSchedule) String() string {
+	return withLocation(s.location, scheduleText(s.schedule))
+}
+
+func (s *locationSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *locationSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *locationSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the next activation of the schedule, evaluated in the location.
+func (s *locationSchedule) Next(after time.Time) time.Time {
+	return nextAfter(s.schedule, after.In(s.location))
+}
+
+// CyclicLocationSchedule ------------------------------------------------------------------
+
+type cyclicLocationSchedule struct {
+	schedule CyclicSchedule
+
+	// location is the location the schedule is evaluated in.
+	location *time.Location
+}
+
+func (s *cyclicLocationSchedule) Calculate(now time.Time) time.Duration {
+	return s.schedule.Calculate(now.In(s.location))
+}
+
+func (s *cyclicLocationSchedule) MoveNextAvtivation(now time.Time) {
+	s.schedule.MoveNextAvtivation(now.In(s.location))
+}
+
+func (s *cyclicLocationSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	sched, ok := withDSTPolicy(s.schedule, policy)
+	if !ok {
+		return s, false
+	}
+
+	return &cyclicLocationSchedule{schedule: sched.(CyclicSchedule), location: s.location}, true
+}
+
+func (s *cyclicLocationSchedule) startTimes(start time.Time) (Schedule, bool) {
+	sched, ok := startTimes(s.schedule, start)
+	if !ok {
+		return s, false
+	}
+
+	return &cyclicLocationSchedule{schedule: sched.(CyclicSchedule), location: s.location}, true
+}
+
+func (s *cyclicLocationSchedule) withPrecision(precision time.Duration) (Schedule, bool) {
+	sched, ok := withPrecision(s.schedule, precision)
+	if !ok {
+		return s, false
+	}
+
+	return &cyclicLocationSchedule{schedule: sched.(CyclicSchedule), location: s.location}, true
+}
+
+// String returns the spec of the schedule prefixed with the location.
+func (s *cyclicLocationSchedule) String() string {
+	return withLocation(s.location, scheduleText(s.schedule))
+}
+
+func (s *cyclicLocationSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *cyclicLocationSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *cyclicLocationSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the next activation of the schedule, evaluated in the location.
+func (s *cyclicLocationSchedule) Next(after time.Time) time.Time {
+	return nextAfter(s.schedule, after.In(s.location))
 }
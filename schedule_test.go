@@ -87,3 +87,166 @@ func TestSchedule(t *testing.T) {
 		}
 	}
 }
+
+func TestInLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10:05 UTC is 19:05 in tokyo.
+	nowTesting := time.Date(2022, 10, 7, 10, 5, 0, 0, time.UTC)
+
+	t.Run("Test Cyclic Schedule", func(t *testing.T) {
+		sched, ok := InLocation(EveryFixed(2*time.Hour), tokyo).(CyclicSchedule)
+		if !ok {
+			t.Fatal("expected cyclic schedule")
+		}
+		sched.MoveNextAvtivation(nowTesting)
+
+		if got, want := sched.Calculate(nowTesting), 55*time.Minute; got != want {
+			t.Fatalf("want: %v got: %v\n", want, got)
+		}
+	})
+
+	t.Run("Test Constant Schedule", func(t *testing.T) {
+		sched := InLocation(In(nowTesting, 5*time.Minute), tokyo)
+		if _, ok := sched.(CyclicSchedule); ok {
+			t.Fatal("expected constant schedule")
+		}
+
+		if got, want := sched.Calculate(nowTesting), 5*time.Minute; got != want {
+			t.Fatalf("want: %v got: %v\n", want, got)
+		}
+	})
+
+	t.Run("Test Nil Location", func(t *testing.T) {
+		want := EveryFixed(2 * time.Hour)
+		if got := InLocation(want, nil); got != want {
+			t.Fatalf("got: %v want: %v", got, want)
+		}
+	})
+
+	t.Run("Test Parsed Descriptor", func(t *testing.T) {
+		sched, err := Parse("TZ=Asia/Tokyo @every 1h")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := sched.(*cyclicLocationSchedule); !ok {
+			t.Fatalf("got: %T want: %T", sched, &cyclicLocationSchedule{})
+		}
+	})
+}
+
+func TestEveryFixed(t *testing.T) {
+	nowTesting := time.Date(2022, 10, 7, 10, 5, 0, 0, time.UTC)
+
+	cases := []struct {
+		schedule Schedule
+		expected time.Time
+	}{
+		// intervals dividing a day, aligned to midnight.
+		{EveryFixed(3 * time.Hour), time.Date(2022, 10, 7, 12, 0, 0, 0, time.UTC)},
+		{EveryFixed(90 * time.Minute), time.Date(2022, 10, 7, 10, 30, 0, 0, time.UTC)},
+		{EveryFixed(45 * time.Second), time.Date(2022, 10, 7, 10, 5, 15, 0, time.UTC)},
+		{EveryFixed(16 * time.Hour), time.Date(2022, 10, 7, 16, 0, 0, 0, time.UTC)},
+		{EveryFixed(12 * time.Hour), time.Date(2022, 10, 7, 12, 0, 0, 0, time.UTC)},
+
+		// intervals dividing a day, aligned to the anchor.
+		{
+			EveryFixedFrom(time.Hour, time.Date(2022, 1, 1, 0, 15, 0, 0, time.UTC)),
+			time.Date(2022, 10, 7, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			EveryFixedFrom(8*time.Hour, time.Date(2022, 1, 1, 9, 0, 0, 0, time.UTC)),
+			time.Date(2022, 10, 7, 17, 0, 0, 0, time.UTC),
+		},
+
+		// intervals of whole days, aligned to 1970-01-01.
+		{EveryFixed(48 * time.Hour), time.Date(2022, 10, 9, 0, 0, 0, 0, time.UTC)},
+		{EveryFixed(7 * 24 * time.Hour), time.Date(2022, 10, 13, 0, 0, 0, 0, time.UTC)},
+
+		// intervals of whole days, aligned to the anchor.
+		{
+			EveryFixedFrom(7*24*time.Hour, time.Date(2022, 10, 3, 9, 0, 0, 0, time.UTC)),
+			time.Date(2022, 10, 10, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			EveryFixedFrom(24*time.Hour, time.Date(2022, 10, 3, 11, 0, 0, 0, time.UTC)),
+			time.Date(2022, 10, 7, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			EveryFixedFrom(14*24*time.Hour, time.Date(2030, 10, 3, 9, 0, 0, 0, time.UTC)),
+			time.Date(2022, 10, 13, 9, 0, 0, 0, time.UTC),
+		},
+
+		// any other interval, aligned to the unix epoch.
+		{EveryFixed(36 * time.Hour), time.Date(2022, 10, 8, 12, 0, 0, 0, time.UTC)},
+		{EveryFixed(7 * time.Minute), time.Date(2022, 10, 7, 10, 11, 0, 0, time.UTC)},
+
+		// any other interval, aligned to the anchor.
+		{
+			EveryFixedFrom(36*time.Hour, time.Date(2022, 10, 1, 6, 0, 0, 0, time.UTC)),
+			time.Date(2022, 10, 8, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			EveryFixedFrom(7*time.Minute, time.Date(2022, 10, 7, 11, 1, 0, 0, time.UTC)),
+			time.Date(2022, 10, 7, 10, 12, 0, 0, time.UTC),
+		},
+
+		// anchors further than a duration can hold.
+		{
+			EveryFixedFrom(7*time.Minute, time.Date(1600, 1, 1, 0, 1, 0, 0, time.UTC)),
+			time.Date(2022, 10, 7, 10, 8, 0, 0, time.UTC),
+		},
+		{
+			EveryFixedFrom(7*time.Minute, time.Date(2400, 1, 1, 0, 1, 0, 0, time.UTC)),
+			time.Date(2022, 10, 7, 10, 8, 0, 0, time.UTC),
+		},
+	}
+
+	for i, c := range cases {
+		sched := c.schedule.(CyclicSchedule)
+		sched.MoveNextAvtivation(nowTesting)
+
+		if got, want := nowTesting.Add(sched.Calculate(nowTesting)), c.expected; !got.Equal(want) {
+			t.Fatalf("case %v: want: %v got: %v\n", i, want, got)
+		}
+	}
+}
+
+func TestEveryFixedMonotonic(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, every := range []time.Duration{
+		time.Second * 7,
+		time.Minute * 7,
+		time.Minute * 30,
+		time.Hour,
+		time.Hour * 5,
+		time.Hour * 24,
+		time.Hour * 36,
+		time.Hour * 24 * 7,
+	} {
+		sched := EveryFixed(every).(*fixedCyclicSchedule)
+
+		// walk over both transitions of 2022.
+		now := time.Date(2022, 3, 12, 0, 0, 0, 0, newYork)
+		end := time.Date(2022, 11, 8, 0, 0, 0, 0, newYork)
+		if every < time.Hour {
+			now = time.Date(2022, 11, 6, 0, 0, 0, 0, newYork)
+		}
+
+		for i := 0; i < 2000 && now.Before(end); i++ {
+			next := sched.Next(now)
+			if !next.After(now) {
+				t.Fatalf("%v: got: %v after: %v", every, next, now)
+			}
+			now = next
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package cronjob_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Lambels/cronjob"
+	"github.com/Lambels/cronjob/schedulertest"
+)
+
+func TestSchedulers(t *testing.T) {
+	t.Run("Linked List", func(t *testing.T) {
+		schedulertest.Run(t, cronjob.NewLinkedList)
+	})
+
+	t.Run("Heap", func(t *testing.T) {
+		schedulertest.Run(t, cronjob.NewHeapScheduler)
+	})
+
+	t.Run("Wheel", func(t *testing.T) {
+		schedulertest.Run(t, func() cronjob.Scheduler {
+			return cronjob.NewWheelScheduler(time.Second)
+		})
+	})
+
+	t.Run("SQL", func(t *testing.T) {
+		schedulertest.Run(t, func() cronjob.Scheduler {
+			return cronjob.NewSQLScheduler(t)
+		})
+	})
+}
@@ -0,0 +1,381 @@
+// Package schedulertest tests implementations of cronjob.Scheduler.
+//
+// example:
+//
+//	func TestScheduler(t *testing.T) {
+//		schedulertest.Run(t, func() cronjob.Scheduler {
+//			return NewScheduler()
+//		})
+//	}
+package schedulertest
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Lambels/cronjob"
+)
+
+// Run runs the tests of the behaviour expected from a scheduler against the schedulers
+// returned by newScheduler (field), which must return a new empty scheduler each call.
+//
+// the schedulers are expected to:
+//
+//   - return the nodes ordered by their next activation from GetAll and RunNow.
+//   - set the next activation of the nodes added, leaving out the nodes whose schedule
+//     has no further activations.
+//   - return -1 from NextCycle if empty, or a duration which doesnt sleep past the
+//     earliest activation, 0 if it passed.
+//   - return the nodes whose activation is now or passed from RunNow, keeping them until
+//     they are cleaned.
+//   - re-calculate the cyclic nodes passed to Clean and remove the others, ignoring the
+//     nodes which were removed.
+//   - remove the node with the id passed to RemoveNode, ignoring unknown ids.
+//   - work when used by a running cronjob, the cronjob calls the scheduler from a single
+//     goroutine at a time so it doesnt need to be safe for concurrent use.
+//
+// the ids of the nodes added are unique.
+func Run(t *testing.T, newScheduler func() cronjob.Scheduler) {
+	t.Run("Empty", func(t *testing.T) { testEmpty(t, newScheduler()) })
+	t.Run("Ordering", func(t *testing.T) { testOrdering(t, newScheduler()) })
+	t.Run("Past Due", func(t *testing.T) { testPastDue(t, newScheduler()) })
+	t.Run("Clean", func(t *testing.T) { testClean(t, newScheduler()) })
+	t.Run("Remove", func(t *testing.T) { testRemove(t, newScheduler()) })
+	t.Run("Cycles", func(t *testing.T) { testCycles(t, newScheduler()) })
+	t.Run("Concurrent Use", func(t *testing.T) { testConcurrentUse(t, newScheduler()) })
+}
+
+// start is the time the tests start at.
+var start = time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+func testEmpty(t *testing.T, s cronjob.Scheduler) {
+	if got, want := s.NextCycle(start), time.Duration(-1); got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := ids(s.RunNow(start)), "[]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := ids(s.GetAll()), "[]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// removing and cleaning nothing doesnt panic.
+	s.RemoveNode(1)
+	s.Clean(start, nil)
+}
+
+func testOrdering(t *testing.T, s cronjob.Scheduler) {
+	expected := map[int]time.Time{
+		1: start.Add(5 * time.Second),
+		2: start.Add(2 * time.Second),
+		3: start.Add(3 * time.Second),
+		4: start.Add(time.Hour),
+	}
+
+	s.AddNode(start, &cronjob.Node{Id: 1, Schedule: cronjob.In(start, 5*time.Second)})
+	s.AddNode(start, &cronjob.Node{Id: 2, Schedule: cronjob.Every(2 * time.Second)})
+	s.AddNode(start, &cronjob.Node{Id: 3, Schedule: cronjob.In(start, 3*time.Second)})
+	s.AddNode(start, &cronjob.Node{Id: 4, Schedule: cronjob.At(start.Add(time.Hour))})
+
+	// exhausted schedules arent added.
+	s.AddNode(start, &cronjob.Node{Id: 5, Schedule: cronjob.TimesFrom(0, start, cronjob.Every(time.Second))})
+
+	nodes := s.GetAll()
+	if got, want := ids(nodes), "[1 2 3 4]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if err := ordered(nodes); err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range nodes {
+		if got, want := node.NextRun, expected[node.Id]; !got.Equal(want) {
+			t.Fatalf("node %v: got: %v want: %v", node.Id, got, want)
+		}
+	}
+
+	if got, want := s.NextCycle(start), 2*time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := s.NextCycle(start.Add(10*time.Second)), time.Duration(0); got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func testPastDue(t *testing.T, s cronjob.Scheduler) {
+	s.AddNode(start, &cronjob.Node{Id: 1, Schedule: cronjob.At(start.Add(-time.Minute))})
+	s.AddNode(start, &cronjob.Node{Id: 2, Schedule: cronjob.At(start.Add(-time.Hour))})
+	s.AddNode(start, &cronjob.Node{Id: 3, Schedule: cronjob.In(start, time.Second)})
+	s.AddNode(start, &cronjob.Node{Id: 4, Schedule: cronjob.At(start.Add(time.Hour))})
+
+	if got, want := s.NextCycle(start), time.Duration(0); got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	nodes := s.RunNow(start)
+	if got, want := ids(nodes), "[1 2]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if err := ordered(nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	// the nodes are kept until cleaned.
+	if got, want := ids(s.RunNow(start)), "[1 2]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// nodes activated at now run.
+	if got, want := ids(s.RunNow(start.Add(time.Second))), "[1 2 3]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	s.Clean(start, nodes)
+	if got, want := ids(s.GetAll()), "[3 4]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := s.NextCycle(start), time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func testClean(t *testing.T, s cronjob.Scheduler) {
+	s.AddNode(start, &cronjob.Node{Id: 1, Schedule: cronjob.EveryFixed(2 * time.Second)})
+	s.AddNode(start, &cronjob.Node{Id: 2, Schedule: cronjob.In(start, 2*time.Second)})
+	s.AddNode(start, &cronjob.Node{Id: 3, Schedule: cronjob.TimesFrom(2, start, cronjob.EveryFixed(2*time.Second))})
+	s.AddNode(start, &cronjob.Node{Id: 4, Schedule: cronjob.In(start, time.Minute)})
+
+	// the cyclic nodes are re-calculated, the constant one removed.
+	now := start.Add(2 * time.Second)
+	nodes := s.RunNow(now)
+	if got, want := ids(nodes), "[1 2 3]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	s.Clean(now, nodes)
+	if got, want := ids(s.GetAll()), "[1 3 4]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := s.NextCycle(now), 2*time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// the exhausted cyclic node is removed.
+	now = start.Add(4 * time.Second)
+	nodes = s.RunNow(now)
+	if got, want := ids(nodes), "[1 3]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	s.Clean(now, nodes)
+	if got, want := ids(s.GetAll()), "[1 4]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// activations which passed before cleaning are skipped.
+	now = start.Add(9 * time.Second)
+	nodes = s.RunNow(now)
+	s.Clean(now, nodes)
+	if got, want := s.NextCycle(now), time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// nodes removed before cleaning arent added back.
+	now = start.Add(10 * time.Second)
+	nodes = s.RunNow(now)
+	s.RemoveNode(1)
+	s.Clean(now, nodes)
+	if got, want := ids(s.GetAll()), "[4]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func testRemove(t *testing.T, s cronjob.Scheduler) {
+	for id := 1; id <= 3; id++ {
+		s.AddNode(start, &cronjob.Node{Id: id, Schedule: cronjob.In(start, time.Duration(id)*time.Second)})
+	}
+
+	s.RemoveNode(1)
+	s.RemoveNode(42)
+	if got, want := ids(s.GetAll()), "[2 3]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := s.NextCycle(start), 2*time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := ids(s.RunNow(start.Add(time.Minute))), "[2 3]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func testCycles(t *testing.T, s cronjob.Scheduler) {
+	now := start
+	r := rand.New(rand.NewSource(1))
+
+	// add adds a node running in up to a few days, cyclic or not.
+	add := func(id int) {
+		var sched cronjob.Schedule
+		switch r.Intn(3) {
+		case 0:
+			sched = cronjob.EveryFixed(time.Duration(1+r.Intn(3600)) * 100 * time.Millisecond)
+		case 1:
+			sched = cronjob.Every(time.Duration(1+r.Intn(600)) * time.Second)
+		default:
+			sched = cronjob.At(now.Add(time.Duration(r.Int63n(int64(72 * time.Hour)))))
+		}
+
+		s.AddNode(now, &cronjob.Node{Id: id, Schedule: sched})
+	}
+
+	id := 1
+	for ; id <= 200; id++ {
+		add(id)
+	}
+
+	for i := 0; i < 2000; i++ {
+		all := s.GetAll()
+		if len(all) == 0 {
+			break
+		}
+		if err := ordered(all); err != nil {
+			t.Fatalf("cycle %v: %v", i, err)
+		}
+
+		// sleeping never goes past an activation, so the nodes run at it.
+		sleep := s.NextCycle(now)
+		if sleep < 0 {
+			t.Fatalf("cycle %v: got: %v want: a positive duration", i, sleep)
+		}
+		if earliest := all[0].NextRun.Sub(now); sleep > earliest {
+			t.Fatalf("cycle %v: got: %v want: at most %v", i, sleep, earliest)
+		}
+		now = now.Add(sleep)
+
+		var due []*cronjob.Node
+		for _, node := range all {
+			if !node.NextRun.After(now) {
+				due = append(due, node)
+			}
+		}
+
+		nodes := s.RunNow(now)
+		if got, want := ids(nodes), ids(due); got != want {
+			t.Fatalf("cycle %v: got: %v want: %v", i, got, want)
+		}
+		for _, node := range nodes {
+			if got, want := node.NextRun, now; !got.Equal(want) {
+				t.Fatalf("cycle %v: node %v: got: %v want: %v", i, node.Id, got, want)
+			}
+		}
+
+		s.Clean(now, nodes)
+
+		// add and remove nodes now and then.
+		if i%20 == 0 {
+			add(id)
+			id++
+
+			s.RemoveNode(1 + r.Intn(id))
+		}
+	}
+}
+
+func testConcurrentUse(t *testing.T, s cronjob.Scheduler) {
+	c := cronjob.New(
+		cronjob.WithScheduler(s),
+		cronjob.WithHighPrecision(),
+		cronjob.WithLogger(log.New(io.Discard, "", 0)),
+	)
+
+	var cyclic int32
+	for i := 0; i < 5; i++ {
+		c.AddFunc(func() error {
+			atomic.AddInt32(&cyclic, 1)
+			return nil
+		}, cronjob.EveryFixed(10*time.Millisecond))
+	}
+
+	c.Start()
+	defer c.Stop()
+
+	// add, remove and list jobs from several goroutines while the jobs run.
+	const workers, jobs = 4, 25
+	var ran [workers * jobs]int32
+
+	wg := &sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < jobs; i++ {
+				n := w*jobs + i
+				c.AddFunc(func() error {
+					atomic.AddInt32(&ran[n], 1)
+					return nil
+				}, cronjob.In(c.Now(), time.Duration(1+n%10)*time.Millisecond))
+
+				// remove a job which never runs now and then.
+				if i%5 == 0 {
+					c.RemoveJob(c.AddFunc(func() error { return nil }, cronjob.In(c.Now(), time.Hour)))
+				}
+				if i%10 == 0 {
+					c.Jobs()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// wait for the jobs to run.
+	deadline := time.Now().Add(5 * time.Second)
+	for n := range ran {
+		for atomic.LoadInt32(&ran[n]) == 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("job %v: got: 0 runs want: 1", n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// the jobs which dont run again are removed.
+	if got, want := len(c.Jobs()), 5; got != want {
+		t.Fatalf("got: %v jobs want: %v", got, want)
+	}
+	for n := range ran {
+		if got, want := atomic.LoadInt32(&ran[n]), int32(1); got != want {
+			t.Fatalf("job %v: got: %v runs want: %v", n, got, want)
+		}
+	}
+	if got := atomic.LoadInt32(&cyclic); got == 0 {
+		t.Fatalf("got: %v runs want: at least 1", got)
+	}
+}
+
+// ordered returns an error if the nodes (field) arent ordered by their next activation.
+func ordered(nodes []*cronjob.Node) error {
+	for i := 1; i < len(nodes); i++ {
+		if nodes[i].NextRun.Before(nodes[i-1].NextRun) {
+			return fmt.Errorf("node %v at %v is before node %v at %v", nodes[i].Id, nodes[i].NextRun, nodes[i-1].Id, nodes[i-1].NextRun)
+		}
+	}
+
+	return nil
+}
+
+// ids returns the ids of the nodes (field), sorted.
+func ids(nodes []*cronjob.Node) string {
+	sorted := make([]int, 0, len(nodes))
+	for _, node := range nodes {
+		sorted = append(sorted, node.Id)
+	}
+
+	sort.Ints(sorted)
+	return fmt.Sprint(sorted)
+}
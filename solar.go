@@ -0,0 +1,246 @@
+package cronjob
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SolarEvent is an event in the daily course of the sun.
+type SolarEvent int
+
+const (
+	// Sunrise is when the upper edge of the sun appears on the horizon.
+	Sunrise SolarEvent = iota
+
+	// Sunset is when the upper edge of the sun disappears below the horizon.
+	Sunset
+
+	// SolarNoon is when the sun is at its highest.
+	SolarNoon
+
+	// CivilDawn is when the center of the sun rises to 6 degrees below the horizon.
+	CivilDawn
+
+	// CivilDusk is when the center of the sun sets to 6 degrees below the horizon.
+	CivilDusk
+
+	// NauticalDawn is when the center of the sun rises to 12 degrees below the horizon.
+	NauticalDawn
+
+	// NauticalDusk is when the center of the sun sets to 12 degrees below the horizon.
+	NauticalDusk
+
+	// AstronomicalDawn is when the center of the sun rises to 18 degrees below the
+	// horizon.
+	AstronomicalDawn
+
+	// AstronomicalDusk is when the center of the sun sets to 18 degrees below the
+	// horizon.
+	AstronomicalDusk
+)
+
+// solarEventNames maps the events to their names.
+var solarEventNames = map[SolarEvent]string{
+	Sunrise:          "sunrise",
+	Sunset:           "sunset",
+	SolarNoon:        "solar-noon",
+	CivilDawn:        "civil-dawn",
+	CivilDusk:        "civil-dusk",
+	NauticalDawn:     "nautical-dawn",
+	NauticalDusk:     "nautical-dusk",
+	AstronomicalDawn: "astronomical-dawn",
+	AstronomicalDusk: "astronomical-dusk",
+}
+
+// String returns the name of the event: sunrise, sunset, solar-noon, civil-dawn, ...
+func (e SolarEvent) String() string {
+	if name, ok := solarEventNames[e]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("SolarEvent(%d)", int(e))
+}
+
+// parseSolarEvent returns the event named name (field).
+func parseSolarEvent(name string) (SolarEvent, error) {
+	for event, eventName := range solarEventNames {
+		if strings.EqualFold(name, eventName) {
+			return event, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown solar event: %q", name)
+}
+
+// julianEpoch is the julian day of the unix epoch.
+const julianEpoch = 2440587.5
+
+// j2000 is the julian day of 2000-01-01 12:00 UTC.
+const j2000 = 2451545.0
+
+// Solar returns a schedule which runs offset (field) after each event (field) at the
+// coordinate latitude (field), longitude (field), in degrees. north and east are
+// positive.
+//
+// example:
+//
+//	cronjob.Solar(cronjob.Sunset, 52.5, 13.4, time.Minute * 30)
+//
+// the schedule runs 30 minutes after each sunset in berlin.
+//
+// the events are computed offline with the NOAA approximation, accurate to a minute or so
+// between the polar circles. days without the event (polar day or night) are skipped.
+func Solar(event SolarEvent, latitude, longitude float64, offset time.Duration) Schedule {
+	return &solarSchedule{
+		event:     event,
+		latitude:  latitude,
+		longitude: longitude,
+		offset:    offset,
+	}
+}
+
+// SolarSchedule ------------------------------------------------------------------
+
+type solarSchedule struct {
+	event SolarEvent
+
+	// latitude and longitude of the coordinate, in degrees.
+	latitude, longitude float64
+
+	// offset is added to the time of the events.
+	offset time.Duration
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *solarSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *solarSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+// String returns the @solar descriptor of the schedule.
+func (s *solarSchedule) String() string {
+	return fmt.Sprintf(
+		"@solar %v %v %v %v",
+		s.event,
+		strconv.FormatFloat(s.latitude, 'f', -1, 64),
+		strconv.FormatFloat(s.longitude, 'f', -1, 64),
+		s.offset,
+	)
+}
+
+func (s *solarSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *solarSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *solarSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first event, delayed by the offset, after: after (field), in the
+// location of after (field).
+//
+// returns the zero time if there is no event in the search window.
+func (s *solarSchedule) Next(after time.Time) time.Time {
+	// the events of a solar day are ordered, start from the day before the one of
+	// after (field).
+	first := math.Floor(julianDay(after.Add(-s.offset))-j2000) - 1
+
+	for n := first; n < first+searchDays; n++ {
+		jd, ok := s.at(n)
+		if !ok {
+			continue
+		}
+
+		if t := fromJulianDay(jd).Add(s.offset); t.After(after) {
+			return t.In(after.Location())
+		}
+	}
+
+	return time.Time{}
+}
+
+// at returns the julian day of the event on the solar day n (field), days since
+// 2000-01-01.
+//
+// reports false if the sun doesnt reach the altitude of the event that day.
+func (s *solarSchedule) at(n float64) (float64, bool) {
+	// mean solar noon at the longitude.
+	meanNoon := n + 0.0008 - s.longitude/360
+
+	// solar mean anomaly, equation of the center and ecliptic longitude.
+	m := math.Mod(357.5291+0.98560028*meanNoon, 360)
+	c := 1.9148*sinDeg(m) + 0.0200*sinDeg(2*m) + 0.0003*sinDeg(3*m)
+	lambda := math.Mod(m+c+180+102.9372, 360)
+
+	transit := j2000 + meanNoon + 0.0053*sinDeg(m) - 0.0069*sinDeg(2*lambda)
+
+	var (
+		altitude float64
+		rising   bool
+	)
+	switch s.event {
+	case SolarNoon:
+		return transit, true
+	case Sunrise, Sunset:
+		altitude, rising = -0.833, s.event == Sunrise
+	case CivilDawn, CivilDusk:
+		altitude, rising = -6, s.event == CivilDawn
+	case NauticalDawn, NauticalDusk:
+		altitude, rising = -12, s.event == NauticalDawn
+	case AstronomicalDawn, AstronomicalDusk:
+		altitude, rising = -18, s.event == AstronomicalDawn
+	default:
+		return 0, false
+	}
+
+	// declination of the sun and its hour angle at the altitude.
+	declination := math.Asin(sinDeg(lambda) * sinDeg(23.4397))
+	cosHourAngle := (sinDeg(altitude) - sinDeg(s.latitude)*math.Sin(declination)) / (cosDeg(s.latitude) * math.Cos(declination))
+
+	// the sun stays above or below the altitude all day.
+	if !(cosHourAngle >= -1 && cosHourAngle <= 1) {
+		return 0, false
+	}
+
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+	if rising {
+		return transit - hourAngle/360, true
+	}
+	return transit + hourAngle/360, true
+}
+
+// julianDay returns the julian day of t (field).
+func julianDay(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(day) + julianEpoch
+}
+
+// fromJulianDay returns the time of the julian day: jd (field), rounded to the second.
+func fromJulianDay(jd float64) time.Time {
+	return time.Unix(int64(math.Round((jd-julianEpoch)*86400)), 0)
+}
+
+// sinDeg returns the sine of deg (field) degrees.
+func sinDeg(deg float64) float64 {
+	return math.Sin(deg * math.Pi / 180)
+}
+
+// cosDeg returns the cosine of deg (field) degrees.
+func cosDeg(deg float64) float64 {
+	return math.Cos(deg * math.Pi / 180)
+}
@@ -0,0 +1,74 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSolar(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		event               SolarEvent
+		latitude, longitude float64
+		offset              time.Duration
+		after               time.Time
+		expected            string
+	}{
+		// berlin on the summer solstice.
+		{Sunrise, 52.52, 13.405, 0, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-06-21T04:44:00+02:00"},
+		{Sunset, 52.52, 13.405, 0, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-06-21T21:34:00+02:00"},
+		{Sunset, 52.52, 13.405, 30 * time.Minute, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-06-21T22:04:00+02:00"},
+		{SolarNoon, 52.52, 13.405, 0, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-06-21T13:09:00+02:00"},
+		{CivilDawn, 52.52, 13.405, 0, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-06-21T03:54:00+02:00"},
+		{CivilDusk, 52.52, 13.405, 0, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-06-21T22:24:00+02:00"},
+
+		// the next day once the event passed.
+		{Sunrise, 52.52, 13.405, 0, time.Date(2026, 6, 21, 12, 0, 0, 0, time.UTC), "2026-06-22T04:44:00+02:00"},
+
+		// an offset moving the event to the day after.
+		{Sunset, 52.52, 13.405, 5 * time.Hour, time.Date(2026, 6, 21, 12, 0, 0, 0, time.UTC), "2026-06-22T02:34:00+02:00"},
+
+		// west and south.
+		{Sunrise, 40.71, -74.006, 0, time.Date(2026, 3, 20, 0, 0, 0, 0, newYork), "2026-03-20T07:00:00-04:00"},
+		{Sunset, 40.71, -74.006, 0, time.Date(2026, 3, 20, 0, 0, 0, 0, newYork), "2026-03-20T19:08:00-04:00"},
+		{Sunrise, -33.87, 151.21, 0, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-06-22T07:01:00+10:00"},
+
+		// days without the event are skipped: midnight sun and polar night in tromsø, no
+		// astronomical night in berlin's summer.
+		{Sunset, 69.65, 18.96, 0, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-07-27T00:37:00+02:00"},
+		{Sunrise, 69.65, 18.96, 0, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), "2027-01-16T11:24:00+01:00"},
+		{AstronomicalDusk, 52.52, 13.405, 0, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC), "2026-07-27T01:11:00+02:00"},
+	}
+
+	for _, c := range cases {
+		sched := Solar(c.event, c.latitude, c.longitude, c.offset)
+
+		got := activate(sched, c.after)
+		want := mustParseTime(t, c.expected)
+		if d := got.Sub(want); d < -time.Minute || d > time.Minute {
+			t.Fatalf("%v at %v, %v: got: %v want: %v", c.event, c.latitude, c.longitude, got, want)
+		}
+
+		if got.Location() != c.after.Location() {
+			t.Fatalf("got: %v want: %v", got.Location(), c.after.Location())
+		}
+	}
+}
+
+func TestSolarOrdered(t *testing.T) {
+	// sunsets are a day apart, whatever the time of the day after is.
+	sched := Solar(Sunset, 52.52, 13.405, -2*time.Hour)
+
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 365; i++ {
+		got := activate(sched, after)
+		if d := got.Sub(after); d <= 0 || d > day+5*time.Minute {
+			t.Fatalf("activation %v: got: %v after: %v", i, got, after)
+		}
+		after = got
+	}
+}
@@ -0,0 +1,259 @@
+package cronjob
+
+import (
+	"math/bits"
+	"strings"
+	"time"
+)
+
+// starBit is set in a field of the spec schedule when the field was written as "*".
+const starBit = 1 << 63
+
+// searchDays is the number of days the spec schedule looks ahead for its next
+// activation before giving up.
+const searchDays = 366 * 8
+
+// SpecSchedule ------------------------------------------------------------------
+
+type specSchedule struct {
+	// each field is a set of bits, the bit at position n is set if the field matches n.
+	second, minute, hour, dom, month, dow uint64
+
+	// lastDays has the bit at position n set for the n-th day before the last day of
+	// the month (L-n), position 0 is the last day (L).
+	lastDays uint64
+
+	// nearestWeekdays has the bit at position n set for the weekday nearest to the
+	// n-th day of the month (nW).
+	nearestWeekdays uint64
+
+	// lastWeekday is set for the last weekday of the month (LW).
+	lastWeekday bool
+
+	// lastDows has the bit at position n set for the last day of week n in the
+	// month (nL).
+	lastDows uint64
+
+	// nthDows has the bit at position k of index n set for the k-th day of week n in
+	// the month (n#k).
+	nthDows [7]uint8
+
+	// fields are the 6 normalized fields the schedule was parsed from.
+	fields []string
+
+	// location is the location the schedule is evaluated in, if nil the location of
+	// the time passed to the schedule is used.
+	location *time.Location
+
+	// dst is the policy used for wall clock times skipped by daylight saving time.
+	dst DSTPolicy
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *specSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *specSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *specSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	if s.dst == policy {
+		return s, false
+	}
+
+	set := *s
+	set.dst = policy
+	return &set, true
+}
+
+// String returns the spec of the schedule, the seconds field is left out if it is 0.
+func (s *specSchedule) String() string {
+	fields := s.fields
+	if len(fields) == len(places) && fields[0] == "0" {
+		fields = fields[1:]
+	}
+
+	return withDST(s.dst, withLocation(s.location, strings.Join(fields, " ")))
+}
+
+func (s *specSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *specSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *specSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first time matched by the schedule after: after (field), in the
+// location of the schedule or the location of after (field) if the schedule has none.
+//
+// returns the zero time if no time matches in the search window.
+func (s *specSchedule) Next(after time.Time) time.Time {
+	if s.location != nil {
+		after = after.In(s.location)
+	}
+	loc := after.Location()
+
+	// activations happen on whole seconds, start looking from the next one.
+	t := after.Truncate(time.Second).Add(time.Second)
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	for i := 0; i < searchDays; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, time.UTC)
+		if i > 0 {
+			hour, min, sec = 0, 0, 0
+		}
+
+		if !s.matchDay(date) {
+			continue
+		}
+
+		// the times of day are wall clock readings, which daylight saving time can skip or
+		// repeat.
+		for h, m, sc, ok := s.clock(hour, min, sec); ok; h, m, sc, ok = s.clock(h, m, sc+1) {
+			wall := time.Date(date.Year(), date.Month(), date.Day(), h, m, sc, 0, time.UTC)
+			if t, ok := wallClock(wall, loc, s.dst); ok && t.After(after) {
+				return t
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchDay reports whether the schedule runs on the day of date (field).
+func (s *specSchedule) matchDay(date time.Time) bool {
+	if s.month&(1<<uint(date.Month())) == 0 {
+		return false
+	}
+
+	domMatch := s.matchDom(date)
+	dowMatch := s.matchDow(date)
+
+	// if any of the day fields is unrestricted, both need to match.
+	if s.dom&starBit > 0 || s.dow&starBit > 0 {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// matchDom reports whether the day of month field matches the day of date (field).
+func (s *specSchedule) matchDom(date time.Time) bool {
+	day := date.Day()
+	if s.dom&(1<<uint(day)) > 0 {
+		return true
+	}
+
+	last := daysIn(date.Year(), date.Month())
+	if s.lastDays&(1<<uint(last-day)) > 0 {
+		return true
+	}
+
+	if s.lastWeekday && day == nearestWeekday(date.Year(), date.Month(), last) {
+		return true
+	}
+
+	for n, ok := nextBit(s.nearestWeekdays, 1, 31); ok; n, ok = nextBit(s.nearestWeekdays, n+1, 31) {
+		if n <= last && day == nearestWeekday(date.Year(), date.Month(), n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchDow reports whether the day of week field matches the day of date (field).
+func (s *specSchedule) matchDow(date time.Time) bool {
+	weekday := date.Weekday()
+	if s.dow&(1<<uint(weekday)) > 0 {
+		return true
+	}
+
+	// the last day of week n is in the last 7 days of the month.
+	if s.lastDows&(1<<uint(weekday)) > 0 && date.Day()+7 > daysIn(date.Year(), date.Month()) {
+		return true
+	}
+
+	return s.nthDows[weekday]&(1<<uint((date.Day()-1)/7+1)) > 0
+}
+
+// clock returns the earliest time of day matched by the schedule which isnt before
+// hour:min:sec.
+func (s *specSchedule) clock(hour, min, sec int) (int, int, int, bool) {
+	return nextClock(s.hour, s.minute, s.second, hour, min, sec)
+}
+
+// nextClock returns the earliest time of day matched by the sets of bits: hours, minutes
+// and seconds (fields) which isnt before hour:min:sec.
+func nextClock(hours, minutes, seconds uint64, hour, min, sec int) (int, int, int, bool) {
+	for h, ok := nextBit(hours, hour, 23); ok; h, ok = nextBit(hours, h+1, 23) {
+		if h != hour {
+			min, sec = 0, 0
+		}
+
+		for m, ok := nextBit(minutes, min, 59); ok; m, ok = nextBit(minutes, m+1, 59) {
+			if m != min {
+				sec = 0
+			}
+
+			if sc, ok := nextBit(seconds, sec, 59); ok {
+				return h, m, sc, true
+			}
+		}
+	}
+
+	return 0, 0, 0, false
+}
+
+// nextBit returns the position of the first bit set in set (field) between from (field)
+// and max (field).
+func nextBit(set uint64, from, max int) (int, bool) {
+	if from > max {
+		return 0, false
+	}
+
+	set &= (1<<uint(max+1) - 1) >> uint(from) << uint(from)
+	if set == 0 {
+		return 0, false
+	}
+
+	return bits.TrailingZeros64(set), true
+}
+
+// daysIn returns the number of days in month (field) of year (field).
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday returns the weekday (monday to friday) nearest to day (field) which
+// is in the same month.
+func nearestWeekday(year int, month time.Month, day int) int {
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+
+	case time.Sunday:
+		if day == daysIn(year, month) {
+			return day - 2
+		}
+		return day + 1
+	}
+
+	return day
+}
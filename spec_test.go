@@ -0,0 +1,209 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpecNext(t *testing.T) {
+	cases := []struct {
+		spec     string
+		after    string
+		expected string
+	}{
+		// simple cases.
+		{"* * * * *", "2022-10-07T10:05:00Z", "2022-10-07T10:06:00Z"},
+		{"* * * * *", "2022-10-07T10:05:30Z", "2022-10-07T10:06:00Z"},
+		{"30 2 * * *", "2022-10-07T10:05:00Z", "2022-10-08T02:30:00Z"},
+		{"30 2 * * *", "2022-10-07T02:29:59Z", "2022-10-07T02:30:00Z"},
+		{"*/15 * * * *", "2022-10-07T10:05:00Z", "2022-10-07T10:15:00Z"},
+		{"0 */6 * * *", "2022-10-07T19:00:00Z", "2022-10-08T00:00:00Z"},
+
+		// wrap around hours, days, months and years.
+		{"59 23 * * *", "2022-10-07T23:59:00Z", "2022-10-08T23:59:00Z"},
+		{"0 0 1 * *", "2022-10-07T10:05:00Z", "2022-11-01T00:00:00Z"},
+		{"0 0 1 1 *", "2022-10-07T10:05:00Z", "2023-01-01T00:00:00Z"},
+		{"0 0 31 * *", "2022-10-31T10:05:00Z", "2022-12-31T00:00:00Z"},
+
+		// leap years.
+		{"0 0 29 2 *", "2022-10-07T10:05:00Z", "2024-02-29T00:00:00Z"},
+		{"0 0 29 feb *", "2024-02-29T00:00:00Z", "2028-02-29T00:00:00Z"},
+
+		// day of week.
+		{"30 2 * * 1-5", "2022-10-07T10:05:00Z", "2022-10-10T02:30:00Z"}, // friday -> monday.
+		{"0 9 * * sat,sun", "2022-10-07T10:05:00Z", "2022-10-08T09:00:00Z"},
+		{"0 9 * * 7", "2022-10-07T10:05:00Z", "2022-10-09T09:00:00Z"},
+
+		// day of month and day of week are OR-ed when both are restricted.
+		{"0 0 13 * 5", "2022-10-07T10:05:00Z", "2022-10-13T00:00:00Z"},
+		{"0 0 20 * 5", "2022-10-07T10:05:00Z", "2022-10-14T00:00:00Z"},
+
+		// and AND-ed when one of them is unrestricted.
+		{"0 0 */1 * 5", "2022-10-07T10:05:00Z", "2022-10-14T00:00:00Z"},
+		{"0 0 13 * *", "2022-10-07T10:05:00Z", "2022-10-13T00:00:00Z"},
+
+		// unsatisfiable.
+		{"0 0 30 2 *", "2022-10-07T10:05:00Z", ""},
+
+		// quartz: ? is the same as *.
+		{"0 9 ? * 1", "2022-10-07T10:05:00Z", "2022-10-10T09:00:00Z"},
+		{"0 9 13 * ?", "2022-10-07T10:05:00Z", "2022-10-13T09:00:00Z"},
+
+		// quartz: last day of month, in short months and leap years.
+		{"0 0 L * ?", "2022-10-07T10:05:00Z", "2022-10-31T00:00:00Z"},
+		{"0 0 L * ?", "2022-11-07T10:05:00Z", "2022-11-30T00:00:00Z"},
+		{"0 0 L * ?", "2023-02-07T10:05:00Z", "2023-02-28T00:00:00Z"},
+		{"0 0 L * ?", "2024-02-07T10:05:00Z", "2024-02-29T00:00:00Z"},
+		{"0 0 L-2 * ?", "2024-02-07T10:05:00Z", "2024-02-27T00:00:00Z"},
+		{"0 0 L-2 * ?", "2023-02-07T10:05:00Z", "2023-02-26T00:00:00Z"},
+		{"0 0 L-30 * ?", "2022-10-07T10:05:00Z", "2022-12-01T00:00:00Z"},
+
+		// quartz: nearest weekday, without leaving the month.
+		{"0 0 15W * ?", "2022-10-07T10:05:00Z", "2022-10-14T00:00:00Z"}, // saturday -> friday.
+		{"0 0 16W * ?", "2022-10-07T10:05:00Z", "2022-10-17T00:00:00Z"}, // sunday -> monday.
+		{"0 0 7W * ?", "2022-10-06T10:05:00Z", "2022-10-07T00:00:00Z"},  // friday.
+		{"0 0 1W * ?", "2022-09-07T10:05:00Z", "2022-10-03T00:00:00Z"},  // saturday 1st -> monday 3rd.
+		{"0 0 31W * ?", "2022-07-07T10:05:00Z", "2022-07-29T00:00:00Z"}, // sunday 31st -> friday 29th.
+		{"0 0 31W * ?", "2022-09-07T10:05:00Z", "2022-10-31T00:00:00Z"}, // no 31st in september.
+		{"0 0 LW * ?", "2022-07-07T10:05:00Z", "2022-07-29T00:00:00Z"},
+		{"0 0 LW * ?", "2022-10-07T10:05:00Z", "2022-10-31T00:00:00Z"},
+		{"0 0 LW * ?", "2026-02-07T10:05:00Z", "2026-02-27T00:00:00Z"},
+
+		// quartz: last day of week of month.
+		{"0 0 ? * 5L", "2022-10-07T10:05:00Z", "2022-10-28T00:00:00Z"},
+		{"0 0 ? * friL", "2022-10-28T10:05:00Z", "2022-11-25T00:00:00Z"},
+		{"0 0 ? * 7L", "2022-10-07T10:05:00Z", "2022-10-30T00:00:00Z"},
+		{"0 0 ? * L", "2022-10-07T10:05:00Z", "2022-10-08T00:00:00Z"},
+
+		// quartz: n-th day of week of month.
+		{"0 0 ? * 2#2", "2022-10-07T10:05:00Z", "2022-10-11T00:00:00Z"},
+		{"0 0 ? * mon#1", "2022-10-07T10:05:00Z", "2022-11-07T00:00:00Z"},
+		{"0 0 ? * mon#5", "2022-10-07T10:05:00Z", "2022-10-31T00:00:00Z"},
+		{"0 0 ? * 1#2,1#4", "2022-10-11T10:05:00Z", "2022-10-24T00:00:00Z"},
+		{"0 0 ? 2 4#5", "2022-10-07T10:05:00Z", "2024-02-29T00:00:00Z"},
+
+		// quartz: restricted day of month and day of week are still OR-ed.
+		{"0 0 L * 1#1", "2022-10-07T10:05:00Z", "2022-10-31T00:00:00Z"},
+		{"0 0 L * 1#1", "2022-10-31T10:05:00Z", "2022-11-07T00:00:00Z"},
+	}
+
+	for _, c := range cases {
+		sched, err := Parse(c.spec)
+		if err != nil {
+			t.Fatalf("%q: %v", c.spec, err)
+		}
+
+		got := sched.(*specSchedule).Next(mustParseTime(t, c.after))
+		if want := mustParseTime(t, c.expected); !got.Equal(want) {
+			t.Fatalf("%q after %v: got: %v want: %v", c.spec, c.after, got, want)
+		}
+	}
+}
+
+func TestSpecNextLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sched, err := Parse("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := sched.(*specSchedule).Next(time.Date(2022, 10, 7, 10, 5, 0, 0, loc))
+	if want := time.Date(2022, 10, 8, 9, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		spec     string
+		after    time.Time
+		expected time.Time
+	}{
+		{
+			spec:     "CRON_TZ=Asia/Tokyo 0 9 * * *",
+			after:    time.Date(2022, 10, 7, 10, 5, 0, 0, time.UTC),
+			expected: time.Date(2022, 10, 8, 9, 0, 0, 0, tokyo),
+		},
+		{
+			spec:     "TZ=Asia/Tokyo 0 9 * * *",
+			after:    time.Date(2022, 10, 7, 10, 5, 0, 0, time.UTC),
+			expected: time.Date(2022, 10, 8, 9, 0, 0, 0, tokyo),
+		},
+		{
+			spec:     "CRON_TZ=UTC @daily",
+			after:    time.Date(2022, 10, 7, 10, 5, 0, 0, tokyo),
+			expected: time.Date(2022, 10, 8, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		sched, err := Parse(c.spec)
+		if err != nil {
+			t.Fatalf("%q: %v", c.spec, err)
+		}
+
+		got := sched.(*specSchedule).Next(c.after)
+		if want := c.expected; !got.Equal(want) {
+			t.Fatalf("%q: got: %v want: %v", c.spec, got, want)
+		}
+		if got, want := got.Location().String(), c.expected.Location().String(); got != want {
+			t.Fatalf("%q: got: %v want: %v", c.spec, got, want)
+		}
+	}
+
+	for _, spec := range []string{"CRON_TZ=Asia/Tokyo", "CRON_TZ=Mars/Olympus 0 9 * * *"} {
+		if _, err := Parse(spec); err == nil {
+			t.Fatalf("%q: expected error", spec)
+		}
+	}
+}
+
+func TestSpecCalculate(t *testing.T) {
+	now := time.Date(2022, 10, 7, 10, 5, 0, 0, time.UTC)
+
+	t.Run("Test Next Activation", func(t *testing.T) {
+		sched, err := Parse("*/10 * * * *")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sched.(CyclicSchedule).MoveNextAvtivation(now)
+
+		if got, want := sched.Calculate(now), 5*time.Minute; got != want {
+			t.Fatalf("got: %v want: %v", got, want)
+		}
+	})
+
+	t.Run("Test No Activation", func(t *testing.T) {
+		sched, err := Parse("0 0 31 4 *")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sched.(CyclicSchedule).MoveNextAvtivation(now)
+
+		if got, want := sched.Calculate(now), never; got != want {
+			t.Fatalf("got: %v want: %v", got, want)
+		}
+	})
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	if value == "" {
+		return time.Time{}
+	}
+
+	v, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
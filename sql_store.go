@@ -0,0 +1,621 @@
+package cronjob
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// the statements of the sql store, written for sqlite.
+const (
+	sqlCreateMigrations = `CREATE TABLE IF NOT EXISTS cronjob_migrations (version INTEGER PRIMARY KEY)`
+	sqlVersion          = `SELECT COALESCE(MAX(version), 0) FROM cronjob_migrations`
+	sqlAddVersion       = `INSERT INTO cronjob_migrations (version) VALUES (?)`
+
+	sqlLoad   = `SELECT id, name, args, schedule, next_run FROM cronjob_jobs ORDER BY id`
+	sqlAll    = `SELECT id, name, args, schedule, next_run FROM cronjob_jobs ORDER BY next_run, id`
+	sqlSave   = `INSERT INTO cronjob_jobs (id, name, args, schedule, next_run, claimed_by, claimed_until) VALUES (?, ?, ?, ?, ?, '', 0) ON CONFLICT (id) DO UPDATE SET name = excluded.name, args = excluded.args, schedule = excluded.schedule, next_run = excluded.next_run`
+	sqlDelete = `DELETE FROM cronjob_jobs WHERE id = ?`
+
+	sqlNextRun    = `SELECT MIN(next_run) FROM cronjob_jobs WHERE (claimed_until <= ? OR claimed_by = ?) AND name IN (SELECT value FROM json_each(?))`
+	sqlClaim      = `UPDATE cronjob_jobs SET claimed_by = ?, claimed_until = ? WHERE next_run <= ? AND claimed_until <= ? AND name IN (SELECT value FROM json_each(?))`
+	sqlClaimed    = `SELECT id, name, args, schedule, next_run FROM cronjob_jobs WHERE claimed_by = ? AND claimed_until > ? AND next_run <= ? ORDER BY next_run, id`
+	sqlReschedule = `UPDATE cronjob_jobs SET next_run = ?, claimed_by = '', claimed_until = 0 WHERE id = ? AND claimed_by = ?`
+	sqlRelease    = `DELETE FROM cronjob_jobs WHERE id = ? AND claimed_by = ?`
+
+	sqlNextId = `UPDATE cronjob_ids SET id = id + 1`
+	sqlId     = `SELECT id FROM cronjob_ids`
+)
+
+// sqlMigrations are the statements of each version of the schema, applied in order by
+// Migrate.
+var sqlMigrations = [][]string{
+	{
+		`CREATE TABLE cronjob_jobs (
+			id INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			args TEXT NOT NULL,
+			schedule TEXT NOT NULL,
+			next_run INTEGER NOT NULL,
+			claimed_by TEXT NOT NULL DEFAULT '',
+			claimed_until INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX cronjob_jobs_next_run ON cronjob_jobs (next_run)`,
+	},
+	{
+		`CREATE TABLE cronjob_ids (id INTEGER NOT NULL)`,
+		`INSERT INTO cronjob_ids (id) SELECT COALESCE(MAX(id), 0) FROM cronjob_jobs`,
+	},
+}
+
+// SQLStoreConf represents a function to configure the behaviour of a sql store.
+type SQLStoreConf func(*SQLStore)
+
+// WithOwner sets the name the scheduler of the store claims the due jobs with, unique
+// to each process sharing the database. defaults to the hostname, the pid and a random
+// suffix.
+func WithOwner(owner string) SQLStoreConf {
+	return func(s *SQLStore) {
+		s.owner = owner
+	}
+}
+
+// WithLease sets how long the jobs claimed by the scheduler of the store are held
+// before other processes can claim them again, in case the process stopped before
+// running them. defaults to a minute.
+func WithLease(lease time.Duration) SQLStoreConf {
+	return func(s *SQLStore) {
+		s.lease = lease
+	}
+}
+
+// WithPollInterval sets the longest the scheduler of the store sleeps, to find the jobs
+// added by other processes. defaults to 10 seconds, 0 or less disables polling for a
+// store used by a single process.
+func WithPollInterval(poll time.Duration) SQLStoreConf {
+	return func(s *SQLStore) {
+		s.poll = poll
+	}
+}
+
+// WithSQLLogger overwrites the logger of the errors of the scheduler of the store.
+func WithSQLLogger(logger *log.Logger) SQLStoreConf {
+	return func(s *SQLStore) {
+		s.logger = logger
+	}
+}
+
+// NewSQLStore returns a store keeping the jobs in the cronjob_jobs table of db (field),
+// created by (*SQLStore).Migrate.
+//
+// the store can be used as the store of a single cronjob (see WithStore), or as the
+// scheduler of the cronjobs of several processes sharing the database (see
+// (*SQLStore).Scheduler).
+//
+// the statements are written for sqlite (3.24 or later, with the json1 extension built
+// in since 3.38), the times are kept as nanoseconds since the unix epoch.
+func NewSQLStore(db *sql.DB, confs ...SQLStoreConf) *SQLStore {
+	s := &SQLStore{
+		db:     db,
+		owner:  defaultOwner(),
+		lease:  time.Minute,
+		poll:   10 * time.Second,
+		logger: log.New(os.Stdout, "[CronJob]", log.Flags()),
+	}
+
+	for _, conf := range confs {
+		conf(s)
+	}
+	return s
+}
+
+// SQLStore implements Store with a database/sql database, see NewSQLStore.
+type SQLStore struct {
+	db     *sql.DB
+	owner  string
+	lease  time.Duration
+	poll   time.Duration
+	logger *log.Logger
+}
+
+// Migrate creates or upgrades the tables of the store to the latest version of their
+// schema, the versions applied are kept in the cronjob_migrations table.
+func (s *SQLStore) Migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, sqlCreateMigrations); err != nil {
+		return fmt.Errorf("cronjob: migrate: %w", err)
+	}
+
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		var version int
+		if err := tx.QueryRowContext(ctx, sqlVersion).Scan(&version); err != nil {
+			return fmt.Errorf("cronjob: migrate: %w", err)
+		}
+
+		for v := version; v < len(sqlMigrations); v++ {
+			for _, stmt := range sqlMigrations[v] {
+				if _, err := tx.ExecContext(ctx, stmt); err != nil {
+					return fmt.Errorf("cronjob: migrate to version %v: %w", v+1, err)
+				}
+			}
+
+			if _, err := tx.ExecContext(ctx, sqlAddVersion, v+1); err != nil {
+				return fmt.Errorf("cronjob: migrate to version %v: %w", v+1, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Load returns all the jobs in the store, ordered by id.
+func (s *SQLStore) Load() ([]StoredJob, error) {
+	rows, err := s.query(s.db, sqlLoad)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]StoredJob, 0, len(rows))
+	for _, row := range rows {
+		var sched ScheduleValue
+		if err := sched.UnmarshalText([]byte(row.schedule)); err != nil {
+			return nil, fmt.Errorf("cronjob: job with id: %v: %w", row.id, err)
+		}
+
+		jobs = append(jobs, StoredJob{
+			Id:       row.id,
+			Name:     row.name,
+			Args:     row.args,
+			Schedule: sched,
+			NextRun:  row.nextRun,
+		})
+	}
+	return jobs, nil
+}
+
+func (s *SQLStore) Save(job StoredJob) error {
+	text, err := job.Schedule.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	return s.save(s.db, job.Id, job.Name, job.Args, string(text), job.NextRun)
+}
+
+func (s *SQLStore) Delete(id int) error {
+	if _, err := s.db.Exec(sqlDelete, id); err != nil {
+		return fmt.Errorf("cronjob: delete job with id: %v: %w", id, err)
+	}
+
+	return nil
+}
+
+// Scheduler returns a scheduler keeping the jobs added with AddNamedFunc in the store,
+// the other jobs are kept in memory. use it with WithScheduler:
+//
+//	cronjob.New(cronjob.WithScheduler(store.Scheduler()))
+//
+// the jobs are shared by the cronjobs using the store, each due job is claimed by a
+// single cronjob which runs it. the claims are released once the jobs are cleaned, or
+// after the lease of the store (see WithLease) if the cronjob stopped before. a cronjob
+// only claims the jobs of the functions registered by its process (see RegisterJob).
+//
+// the cronjobs using the scheduler take the ids of their jobs from the database, and
+// wake up at least every poll interval of the store (see WithPollInterval) to find the
+// jobs added by other processes.
+func (s *SQLStore) Scheduler() Scheduler {
+	return &sqlScheduler{
+		store:     s,
+		local:     &heapScheduler{index: make(map[int]int)},
+		nodes:     make(map[int]*Node),
+		schedules: make(map[int]string),
+		precision: defaultPrecision,
+	}
+}
+
+// sqlExecer is implemented by *sql.DB and *sql.Tx.
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// sqlRow is a row of the cronjob_jobs table.
+type sqlRow struct {
+	id       int
+	name     string
+	args     []string
+	schedule string
+	nextRun  time.Time
+}
+
+// query returns the rows of the cronjob_jobs table selected by the query: query (field).
+func (s *SQLStore) query(db sqlExecer, query string, args ...interface{}) ([]sqlRow, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cronjob: query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var result []sqlRow
+	for rows.Next() {
+		var (
+			row     sqlRow
+			args    string
+			nextRun int64
+		)
+
+		if err := rows.Scan(&row.id, &row.name, &args, &row.schedule, &nextRun); err != nil {
+			return nil, fmt.Errorf("cronjob: query jobs: %w", err)
+		}
+		if err := json.Unmarshal([]byte(args), &row.args); err != nil {
+			return nil, fmt.Errorf("cronjob: job with id: %v: %w", row.id, err)
+		}
+		if len(row.args) == 0 {
+			row.args = nil
+		}
+
+		row.nextRun = fromUnixNano(nextRun)
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cronjob: query jobs: %w", err)
+	}
+	return result, nil
+}
+
+// save adds the job or replaces the job with the same id, keeping its claim.
+func (s *SQLStore) save(db sqlExecer, id int, name string, args []string, schedule string, nextRun time.Time) error {
+	if args == nil {
+		args = []string{}
+	}
+
+	b, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(sqlSave, id, name, string(b), schedule, unixNano(nextRun)); err != nil {
+		return fmt.Errorf("cronjob: save job with id: %v: %w", id, err)
+	}
+	return nil
+}
+
+// tx runs fn (field) in a transaction, committed if fn returns nil.
+func (s *SQLStore) tx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SQLScheduler ------------------------------------------------------------------
+
+type sqlScheduler struct {
+	store *SQLStore
+
+	// local holds the nodes of the jobs which arent stored.
+	local *heapScheduler
+
+	// nodes holds the nodes of the stored jobs read by the scheduler, by id.
+	nodes map[int]*Node
+
+	// schedules holds the schedules of nodes as stored, before their intervals are
+	// raised to precision.
+	schedules map[int]string
+
+	// precision is the precision of the cronjob using the scheduler.
+	precision time.Duration
+}
+
+func (s *sqlScheduler) setPrecision(precision time.Duration) {
+	s.precision = precision
+}
+
+// NextCycle gets the duration of sleeping before activating, at most the poll interval
+// of the store.
+//
+// returns -1 if there are no jobs to run and polling is disabled.
+func (s *sqlScheduler) NextCycle(now time.Time) time.Duration {
+	sleep := time.Duration(-1)
+	if s.store.poll > 0 {
+		sleep = s.store.poll
+	}
+
+	var next sql.NullInt64
+	if err := s.store.db.QueryRow(sqlNextRun, unixNano(now), s.store.owner, registeredNames()).Scan(&next); err != nil {
+		s.store.logger.Printf("cant get the next activation: %v\n", err)
+	} else if next.Valid {
+		if d := fromUnixNano(next.Int64).Sub(now); sleep == -1 || d < sleep {
+			sleep = d
+		}
+	}
+
+	if d := s.local.NextCycle(now); d >= 0 && (sleep == -1 || d < sleep) {
+		sleep = d
+	}
+	if sleep == -1 {
+		return -1
+	}
+	if sleep < 0 {
+		return 0
+	}
+	return sleep
+}
+
+// RunNow claims the stored jobs scheduled to run now or in the past, and returns their
+// nodes with the local ones in the order of their activations.
+//
+// the jobs claimed before and not cleaned yet are returned again, the jobs of functions
+// which arent registered are left to the processes registering them.
+func (s *sqlScheduler) RunNow(now time.Time) []*Node {
+	nodes := s.local.RunNow(now)
+
+	var rows []sqlRow
+	err := s.store.tx(context.Background(), func(tx *sql.Tx) error {
+		until := unixNano(now.Add(s.store.lease))
+		if _, err := tx.Exec(sqlClaim, s.store.owner, until, unixNano(now), unixNano(now), registeredNames()); err != nil {
+			return fmt.Errorf("cronjob: claim jobs: %w", err)
+		}
+
+		var err error
+		rows, err = s.store.query(tx, sqlClaimed, s.store.owner, unixNano(now), unixNano(now))
+		return err
+	})
+	if err != nil {
+		s.store.logger.Printf("cant claim the due jobs: %v\n", err)
+	}
+
+	for _, row := range rows {
+		if node := s.nodeOf(row); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+
+	sortNodes(nodes)
+	return nodes
+}
+
+// GetAll returns all the nodes in the order of their activations, including the stored
+// ones claimed by other cronjobs.
+func (s *sqlScheduler) GetAll() []*Node {
+	nodes := s.local.GetAll()
+
+	rows, err := s.store.query(s.store.db, sqlAll)
+	if err != nil {
+		s.store.logger.Printf("cant get the stored jobs: %v\n", err)
+		return nodes
+	}
+
+	// forget the nodes of the jobs which left the store.
+	read := make(map[int]*Node, len(rows))
+	for _, row := range rows {
+		if node := s.nodeOf(row); node != nil {
+			read[node.Id] = node
+			nodes = append(nodes, node)
+		}
+	}
+	s.nodes = read
+	for id := range s.schedules {
+		if _, ok := read[id]; !ok {
+			delete(s.schedules, id)
+		}
+	}
+
+	sortNodes(nodes)
+	return nodes
+}
+
+// AddNode saves the node (field) of a job added with AddNamedFunc in the store based on
+// its next activation, adds the other nodes in memory.
+//
+// no-op if node is nil or its schedule has no further activations.
+func (s *sqlScheduler) AddNode(now time.Time, node *Node) {
+	if node == nil {
+		return
+	}
+	if node.Job == nil || node.Job.name == "" {
+		s.local.AddNode(now, node)
+		return
+	}
+
+	node.NextRun = nextRun(now, node.Schedule)
+	if node.NextRun.IsZero() {
+		return
+	}
+
+	text, err := marshalText(node.Schedule)
+	if err == nil {
+		err = s.store.save(s.store.db, node.Id, node.Job.name, node.Job.args, string(text), node.NextRun)
+	}
+	if err != nil {
+		s.store.logger.Printf("cant add job with id: %v: %v\n", node.Id, err)
+		return
+	}
+
+	s.nodes[node.Id] = node
+	s.schedules[node.Id] = string(text)
+}
+
+// RemoveNode removes the node with the given id.
+//
+// no-op if node not found.
+func (s *sqlScheduler) RemoveNode(id int) {
+	s.local.RemoveNode(id)
+
+	if err := s.store.Delete(id); err != nil {
+		s.store.logger.Println(err)
+	}
+	delete(s.nodes, id)
+	delete(s.schedules, id)
+}
+
+// Clean removes the nodes (field) and re-calculates the cyclic ones, releasing the
+// claims of the stored jobs in a transaction.
+//
+// stored jobs which were removed or claimed by another cronjob since arent changed.
+func (s *sqlScheduler) Clean(now time.Time, nodes []*Node) {
+	var local, stored []*Node
+	for _, node := range nodes {
+		if s.nodes[node.Id] == node {
+			stored = append(stored, node)
+		} else {
+			local = append(local, node)
+		}
+	}
+
+	s.local.Clean(now, local)
+	if len(stored) == 0 {
+		return
+	}
+
+	// the next activations of the cyclic nodes, the zero time for the others.
+	nexts := make([]time.Time, len(stored))
+	for i, node := range stored {
+		if _, cyclic := node.Schedule.(CyclicSchedule); cyclic {
+			nexts[i] = rescheduled(now, node)
+		}
+	}
+
+	err := s.store.tx(context.Background(), func(tx *sql.Tx) error {
+		for i, node := range stored {
+			next := nexts[i]
+
+			var err error
+			if next.IsZero() {
+				_, err = tx.Exec(sqlRelease, node.Id, s.store.owner)
+			} else {
+				_, err = tx.Exec(sqlReschedule, unixNano(next), node.Id, s.store.owner)
+			}
+			if err != nil {
+				return fmt.Errorf("cronjob: clean job with id: %v: %w", node.Id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.store.logger.Printf("cant clean the jobs ran: %v\n", err)
+		return
+	}
+
+	for i, node := range stored {
+		if nexts[i].IsZero() {
+			delete(s.nodes, node.Id)
+			delete(s.schedules, node.Id)
+			continue
+		}
+		node.NextRun = nexts[i]
+	}
+}
+
+// node returns the node with the given id, nil if not found.
+func (s *sqlScheduler) node(id int) *Node {
+	if node := s.local.node(id); node != nil {
+		return node
+	}
+
+	return s.nodes[id]
+}
+
+// nodeOf returns the node of the row (field), the node read before if its job didnt
+// change.
+//
+// returns nil if the function of the job isnt registered or its schedule cant be
+// parsed.
+func (s *sqlScheduler) nodeOf(row sqlRow) *Node {
+	if node, ok := s.nodes[row.id]; ok && node.Job.name == row.name && s.schedules[row.id] == row.schedule {
+		node.NextRun = row.nextRun
+		return node
+	}
+
+	job, err := namedJob(row.name, row.args)
+	if err != nil {
+		s.store.logger.Printf("cant read job with id: %v: %v\n", row.id, err)
+		return nil
+	}
+
+	sched, err := textParser.Parse(row.schedule)
+	if err != nil {
+		s.store.logger.Printf("cant read job with id: %v: %v\n", row.id, err)
+		return nil
+	}
+
+	// the job runs like the jobs added to the cronjob.
+	job.precision = s.precision
+	sched, _ = withPrecision(sched, s.precision)
+
+	node := &Node{
+		Id:       row.id,
+		Schedule: sched,
+		Job:      job,
+		NextRun:  row.nextRun,
+	}
+	s.nodes[row.id] = node
+	s.schedules[row.id] = row.schedule
+	return node
+}
+
+// nextId returns the next id of the jobs of the cronjobs sharing the store.
+func (s *sqlScheduler) nextId() (int, error) {
+	var id int
+	err := s.store.tx(context.Background(), func(tx *sql.Tx) error {
+		if _, err := tx.Exec(sqlNextId); err != nil {
+			return err
+		}
+		return tx.QueryRow(sqlId).Scan(&id)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cronjob: next id: %w", err)
+	}
+
+	return id, nil
+}
+
+// registeredNames returns the names of the registered functions as a json array.
+func registeredNames() string {
+	jobsMu.RLock()
+	names := make([]string, 0, len(registeredJobs))
+	for name := range registeredJobs {
+		names = append(names, name)
+	}
+	jobsMu.RUnlock()
+
+	b, _ := json.Marshal(names)
+	return string(b)
+}
+
+// unixNano returns t (field) in nanoseconds since the unix epoch, 0 for the zero time.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano returns the time of nanoseconds since the unix epoch: n (field), the zero
+// time for 0.
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// defaultOwner returns the hostname, the pid and a random suffix.
+func defaultOwner() string {
+	host, _ := os.Hostname()
+
+	b := make([]byte, 4)
+	rand.Read(b)
+	return fmt.Sprintf("%v-%v-%v", host, os.Getpid(), hex.EncodeToString(b))
+}
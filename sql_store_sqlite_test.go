@@ -0,0 +1,300 @@
+package cronjob
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	sql.Register("cronjob-sqlite3", sqliteDriver{})
+}
+
+// sqliteEnd is printed by the sqlite3 shell after the output of each statement.
+const sqliteEnd = "--cronjob-end--"
+
+// sqliteDriver runs the statements of the sql store on a real sqlite database, through
+// the sqlite3 command line shell. each connection is a shell opened on the database file
+// named by the dsn.
+type sqliteDriver struct{}
+
+func (sqliteDriver) Open(name string) (driver.Conn, error) {
+	cmd := exec.Command("sqlite3", "-json", "-cmd", ".timeout 5000", name)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	// the errors are read in order with the output.
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &sqliteConn{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout)}, nil
+}
+
+type sqliteConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+func (c *sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return &sqliteStmt{conn: c, query: query}, nil
+}
+
+func (c *sqliteConn) Close() error {
+	c.stdin.Close()
+	return c.cmd.Wait()
+}
+
+func (c *sqliteConn) Begin() (driver.Tx, error) {
+	_, err := c.run("BEGIN IMMEDIATE")
+	return c, err
+}
+
+func (c *sqliteConn) Commit() error {
+	_, err := c.run("COMMIT")
+	return err
+}
+
+func (c *sqliteConn) Rollback() error {
+	_, err := c.run("ROLLBACK")
+	return err
+}
+
+// run runs the statements: stmts (field), returning the rows selected by each of them
+// which selected any.
+func (c *sqliteConn) run(stmts string) ([]*fakeRows, error) {
+	if _, err := fmt.Fprintf(c.stdin, "%s;\n.print %s\n", stmts, sqliteEnd); err != nil {
+		return nil, err
+	}
+
+	var output strings.Builder
+	for {
+		line, err := c.stdout.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("sqlite3: %v: %w", output.String(), err)
+		}
+		if line == sqliteEnd+"\n" {
+			break
+		}
+		output.WriteString(line)
+	}
+
+	// the output is json arrays of the rows selected, or an error.
+	text := output.String()
+	if text != "" && text[0] != '[' {
+		return nil, errors.New(strings.TrimSpace(text))
+	}
+
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+
+	var results []*fakeRows
+	for dec.More() {
+		rows, err := sqliteRows(dec)
+		if err != nil {
+			return nil, fmt.Errorf("sqlite3: %q: %w", text, err)
+		}
+		results = append(results, rows)
+	}
+	return results, nil
+}
+
+// sqliteRows decodes a json array of rows, keeping the order of their columns.
+func sqliteRows(dec *json.Decoder) (*fakeRows, error) {
+	rows := &fakeRows{}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	for dec.More() {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		var (
+			columns []string
+			values  []driver.Value
+		)
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			if n, ok := value.(json.Number); ok {
+				if value, err = n.Int64(); err != nil {
+					return nil, err
+				}
+			}
+			columns = append(columns, key.(string))
+			values = append(values, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+
+		rows.columns = columns
+		rows.values = append(rows.values, values)
+	}
+
+	_, err := dec.Token()
+	return rows, err
+}
+
+type sqliteStmt struct {
+	conn  *sqliteConn
+	query string
+}
+
+func (s *sqliteStmt) Close() error {
+	return nil
+}
+
+func (s *sqliteStmt) NumInput() int {
+	return -1
+}
+
+func (s *sqliteStmt) Exec(args []driver.Value) (driver.Result, error) {
+	query, err := sqliteBind(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.conn.run(query + ";\nSELECT changes() AS changes")
+	if err != nil {
+		return nil, err
+	}
+	changes := results[len(results)-1].values[0][0].(int64)
+	return driver.RowsAffected(changes), nil
+}
+
+func (s *sqliteStmt) Query(args []driver.Value) (driver.Rows, error) {
+	query, err := sqliteBind(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.conn.run(query)
+	if err != nil || len(results) == 0 {
+		return &fakeRows{}, err
+	}
+	return results[0], nil
+}
+
+// sqliteBind replaces the placeholders of query (field) with the literals of args (field).
+func sqliteBind(query string, args []driver.Value) (string, error) {
+	var (
+		b      strings.Builder
+		quoted bool
+		i      int
+	)
+	for _, r := range query {
+		if r == '\'' {
+			quoted = !quoted
+		}
+		if r != '?' || quoted {
+			b.WriteRune(r)
+			continue
+		}
+
+		if i == len(args) {
+			return "", fmt.Errorf("sqlite3: %q: want more than %v args", query, len(args))
+		}
+		switch arg := args[i].(type) {
+		case nil:
+			b.WriteString("NULL")
+		case int64:
+			b.WriteString(strconv.FormatInt(arg, 10))
+		case string:
+			b.WriteString("'" + strings.ReplaceAll(arg, "'", "''") + "'")
+		default:
+			return "", fmt.Errorf("sqlite3: cant bind %T", arg)
+		}
+		i++
+	}
+
+	return b.String(), nil
+}
+
+// openSQLiteStore returns a migrated sql store with a new sqlite database, and a function
+// opening other stores sharing it.
+//
+// skips the test if the sqlite3 shell isnt installed.
+func openSQLiteStore(t *testing.T, confs ...SQLStoreConf) (*SQLStore, func(...SQLStoreConf) *SQLStore) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 isnt installed")
+	}
+	path := filepath.Join(t.TempDir(), "cronjob.db")
+
+	open := func(confs ...SQLStoreConf) *SQLStore {
+		db, err := sql.Open("cronjob-sqlite3", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		confs = append([]SQLStoreConf{WithSQLLogger(log.New(io.Discard, "", 0))}, confs...)
+		return NewSQLStore(db, confs...)
+	}
+
+	store := open(confs...)
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return store, open
+}
+
+func TestSQLite(t *testing.T) {
+	t.Run("Store", func(t *testing.T) {
+		testSQLStore(t, openSQLiteStore)
+	})
+
+	t.Run("Scheduler", func(t *testing.T) {
+		testSQLScheduler(t, openSQLiteStore)
+	})
+
+	t.Run("Unregistered", func(t *testing.T) {
+		store, _ := openSQLiteStore(t, WithOwner("a"))
+		now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+		if err := store.Save(StoredJob{Id: 1, Name: "sql-unregistered", Schedule: ScheduleValue{EveryFixed(time.Minute)}, NextRun: now.Add(-time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := ids(store.Scheduler().RunNow(now)), "[]"; got != want {
+			t.Fatalf("got: %v want: %v", got, want)
+		}
+
+		// the jobs of functions which arent registered arent claimed.
+		var claimedBy string
+		if err := store.db.QueryRow(`SELECT claimed_by FROM cronjob_jobs WHERE id = 1`).Scan(&claimedBy); err != nil {
+			t.Fatal(err)
+		}
+		if claimedBy != "" {
+			t.Fatalf("got: %v want: no claim", claimedBy)
+		}
+	})
+}
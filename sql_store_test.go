@@ -0,0 +1,627 @@
+package cronjob
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func init() {
+	sql.Register("cronjob-fake", fakeDriver{})
+}
+
+// fakeDriver is a stand-in for a sqlite driver, running the statements of the sql store
+// on tables in memory. the connections opened with the same name share the tables.
+type fakeDriver struct{}
+
+var (
+	fakeDBsMu sync.Mutex
+	fakeDBs   = map[string]*fakeDB{}
+)
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+
+	db, ok := fakeDBs[name]
+	if !ok {
+		db = &fakeDB{tables: make(map[string]bool), jobs: make(map[int]fakeJob)}
+		fakeDBs[name] = db
+	}
+	return &fakeConn{db: db}, nil
+}
+
+// fakeDB holds the tables of a fake database.
+type fakeDB struct {
+	// txMu serializes the transactions, like the database lock of sqlite.
+	txMu sync.Mutex
+
+	mu       sync.Mutex
+	tables   map[string]bool
+	versions []int64
+	jobs     map[int]fakeJob
+	ids      int64
+}
+
+// fakeJob is a row of the cronjob_jobs table.
+type fakeJob struct {
+	id                              int64
+	name, args, schedule, claimedBy string
+	nextRun, claimedUntil           int64
+}
+
+type fakeConn struct {
+	db *fakeDB
+
+	// snapshot holds the tables at the start of the transaction, restored on rollback.
+	snapshot *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.txMu.Lock()
+
+	c.db.mu.Lock()
+	c.snapshot = &fakeDB{
+		tables:   make(map[string]bool, len(c.db.tables)),
+		versions: append([]int64(nil), c.db.versions...),
+		jobs:     make(map[int]fakeJob, len(c.db.jobs)),
+		ids:      c.db.ids,
+	}
+	for table := range c.db.tables {
+		c.snapshot.tables[table] = true
+	}
+	for id, job := range c.db.jobs {
+		c.snapshot.jobs[id] = job
+	}
+	c.db.mu.Unlock()
+
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.snapshot = nil
+	c.db.txMu.Unlock()
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.db.mu.Lock()
+	c.db.tables, c.db.versions = c.snapshot.tables, c.snapshot.versions
+	c.db.jobs, c.db.ids = c.snapshot.jobs, c.snapshot.ids
+	c.db.mu.Unlock()
+
+	c.snapshot = nil
+	c.db.txMu.Unlock()
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	_, n, err := s.run(args)
+	return driver.RowsAffected(n), err
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows, _, err := s.run(args)
+	return rows, err
+}
+
+// run runs the statement with args (field), returning the rows selected and the number
+// of rows changed.
+func (s *fakeStmt) run(args []driver.Value) (*fakeRows, int64, error) {
+	db := s.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	arg := func(i int) int64 { return args[i].(int64) }
+	str := func(i int) string { return args[i].(string) }
+	// names returns the json array of names at i (field) as a set.
+	names := func(i int) (map[string]bool, error) {
+		var list []string
+		if err := json.Unmarshal([]byte(str(i)), &list); err != nil {
+			return nil, err
+		}
+
+		set := make(map[string]bool, len(list))
+		for _, name := range list {
+			set[name] = true
+		}
+		return set, nil
+	}
+
+	create := func(table string) error {
+		if db.tables[table] {
+			return fmt.Errorf("table %v already exists", table)
+		}
+		db.tables[table] = true
+		return nil
+	}
+	if s.query == sqlCreateMigrations {
+		db.tables["cronjob_migrations"] = true
+		return &fakeRows{}, 0, nil
+	}
+	if s.query == sqlMigrations[0][0] {
+		return &fakeRows{}, 0, create("cronjob_jobs")
+	}
+	if s.query == sqlMigrations[1][0] {
+		return &fakeRows{}, 0, create("cronjob_ids")
+	}
+
+	// select the jobs matching: match (field).
+	selectJobs := func(match func(fakeJob) bool, byId bool) *fakeRows {
+		var jobs []fakeJob
+		for _, job := range db.jobs {
+			if match(job) {
+				jobs = append(jobs, job)
+			}
+		}
+
+		sort.Slice(jobs, func(i, j int) bool {
+			if !byId && jobs[i].nextRun != jobs[j].nextRun {
+				return jobs[i].nextRun < jobs[j].nextRun
+			}
+			return jobs[i].id < jobs[j].id
+		})
+
+		rows := &fakeRows{columns: []string{"id", "name", "args", "schedule", "next_run"}}
+		for _, job := range jobs {
+			rows.values = append(rows.values, []driver.Value{job.id, job.name, job.args, job.schedule, job.nextRun})
+		}
+		return rows
+	}
+	// update the jobs matching: match (field) with: update (field).
+	updateJobs := func(match func(fakeJob) bool, update func(*fakeJob)) int64 {
+		var n int64
+		for id, job := range db.jobs {
+			if match(job) {
+				if update == nil {
+					delete(db.jobs, id)
+				} else {
+					update(&job)
+					db.jobs[id] = job
+				}
+				n++
+			}
+		}
+		return n
+	}
+	all := func(fakeJob) bool { return true }
+
+	switch s.query {
+	case sqlVersion:
+		var version int64
+		for _, v := range db.versions {
+			if v > version {
+				version = v
+			}
+		}
+		return &fakeRows{columns: []string{"version"}, values: [][]driver.Value{{version}}}, 0, nil
+
+	case sqlAddVersion:
+		db.versions = append(db.versions, arg(0))
+		return &fakeRows{}, 1, nil
+
+	case sqlMigrations[0][1]:
+		return &fakeRows{}, 0, nil
+
+	case sqlMigrations[1][1]:
+		for id := range db.jobs {
+			if int64(id) > db.ids {
+				db.ids = int64(id)
+			}
+		}
+		return &fakeRows{}, 1, nil
+	}
+
+	if !db.tables["cronjob_jobs"] || !db.tables["cronjob_ids"] {
+		return nil, 0, errors.New("no such table")
+	}
+
+	switch s.query {
+	case sqlLoad:
+		return selectJobs(all, true), 0, nil
+
+	case sqlAll:
+		return selectJobs(all, false), 0, nil
+
+	case sqlSave:
+		// the claim of a replaced job is kept.
+		job := db.jobs[int(arg(0))]
+		job.id, job.name, job.args, job.schedule, job.nextRun = arg(0), str(1), str(2), str(3), arg(4)
+		db.jobs[int(arg(0))] = job
+		return &fakeRows{}, 1, nil
+
+	case sqlDelete:
+		return &fakeRows{}, updateJobs(func(job fakeJob) bool { return job.id == arg(0) }, nil), nil
+
+	case sqlNextRun:
+		registered, err := names(2)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var next driver.Value
+		for _, job := range db.jobs {
+			if (job.claimedUntil <= arg(0) || job.claimedBy == str(1)) && registered[job.name] && (next == nil || job.nextRun < next.(int64)) {
+				next = job.nextRun
+			}
+		}
+		return &fakeRows{columns: []string{"next_run"}, values: [][]driver.Value{{next}}}, 0, nil
+
+	case sqlClaim:
+		registered, err := names(4)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		n := updateJobs(func(job fakeJob) bool {
+			return job.nextRun <= arg(2) && job.claimedUntil <= arg(3) && registered[job.name]
+		}, func(job *fakeJob) {
+			job.claimedBy, job.claimedUntil = str(0), arg(1)
+		})
+		return &fakeRows{}, n, nil
+
+	case sqlClaimed:
+		return selectJobs(func(job fakeJob) bool {
+			return job.claimedBy == str(0) && job.claimedUntil > arg(1) && job.nextRun <= arg(2)
+		}, false), 0, nil
+
+	case sqlReschedule:
+		n := updateJobs(func(job fakeJob) bool {
+			return job.id == arg(1) && job.claimedBy == str(2)
+		}, func(job *fakeJob) {
+			job.nextRun, job.claimedBy, job.claimedUntil = arg(0), "", 0
+		})
+		return &fakeRows{}, n, nil
+
+	case sqlRelease:
+		return &fakeRows{}, updateJobs(func(job fakeJob) bool { return job.id == arg(0) && job.claimedBy == str(1) }, nil), nil
+
+	case sqlNextId:
+		db.ids++
+		return &fakeRows{}, 1, nil
+
+	case sqlId:
+		return &fakeRows{columns: []string{"id"}, values: [][]driver.Value{{db.ids}}}, 0, nil
+	}
+
+	return nil, 0, fmt.Errorf("unexpected statement: %v", s.query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// openFakeStore returns a migrated sql store with a new fake database named after the
+// test, and a function opening other stores sharing it.
+func openFakeStore(t *testing.T, confs ...SQLStoreConf) (*SQLStore, func(...SQLStoreConf) *SQLStore) {
+	return openFakeDB(t, t.Name(), confs...)
+}
+
+// openFakeDB returns a migrated sql store with a new fake database named: name (field),
+// and a function opening other stores sharing it.
+func openFakeDB(t *testing.T, name string, confs ...SQLStoreConf) (*SQLStore, func(...SQLStoreConf) *SQLStore) {
+	open := func(confs ...SQLStoreConf) *SQLStore {
+		db, err := sql.Open("cronjob-fake", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		confs = append([]SQLStoreConf{WithSQLLogger(log.New(io.Discard, "", 0))}, confs...)
+		return NewSQLStore(db, confs...)
+	}
+
+	t.Cleanup(func() {
+		fakeDBsMu.Lock()
+		delete(fakeDBs, name)
+		fakeDBsMu.Unlock()
+	})
+
+	store := open(confs...)
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return store, open
+}
+
+func TestSQLStoreMigrate(t *testing.T) {
+	store, _ := openFakeStore(t)
+
+	// migrating again applies nothing.
+	if err := store.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	db := fakeDBs[t.Name()]
+	if got, want := fmt.Sprint(db.versions), "[1 2]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+// openStore returns a migrated sql store with a new database, and a function opening
+// other stores sharing it.
+type openStore func(t *testing.T, confs ...SQLStoreConf) (*SQLStore, func(...SQLStoreConf) *SQLStore)
+
+func TestSQLStore(t *testing.T) {
+	testSQLStore(t, openFakeStore)
+}
+
+func testSQLStore(t *testing.T, openStore openStore) {
+	store, _ := openStore(t)
+	at := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, job := range []StoredJob{
+		{Id: 1, Name: "a", Schedule: ScheduleValue{At(at)}, NextRun: at},
+		{Id: 2, Name: "b", Args: []string{"x"}, Schedule: ScheduleValue{EveryFixed(time.Hour)}},
+		{Id: 3, Name: "c", Schedule: ScheduleValue{EveryFixed(time.Minute)}},
+		{Id: 2, Name: "b", Args: []string{"x"}, Schedule: ScheduleValue{EveryFixed(time.Hour)}, NextRun: at},
+	} {
+		if err := store.Save(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"a @at 2030-01-01T09:00:00Z 2030-01-01T09:00:00Z",
+		"b @every-fixed 1h0m0s 2030-01-01T09:00:00Z",
+	}
+	if got := describeJobs(jobs); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := fmt.Sprint(jobs[0].Args, jobs[1].Args), "[] [x]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestSQLScheduler(t *testing.T) {
+	testSQLScheduler(t, openFakeStore)
+}
+
+func testSQLScheduler(t *testing.T, openStore openStore) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	storeA, open := openStore(t, WithOwner("a"), WithLease(time.Minute), WithPollInterval(10*time.Second))
+	storeB := open(WithOwner("b"), WithLease(time.Minute), WithPollInterval(10*time.Second))
+
+	a, b := storeA.Scheduler(), storeB.Scheduler()
+	if got, want := a.NextCycle(now), 10*time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	named := func(id int, sched Schedule) *Node {
+		job, err := namedJob("store-test", []string{fmt.Sprint(id)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Node{Id: id, Schedule: sched, Job: job}
+	}
+
+	a.AddNode(now, named(1, In(now, time.Second)))
+	a.AddNode(now, named(2, EveryFixed(time.Minute)))
+	a.AddNode(now, &Node{Id: 3, Schedule: In(now, 2*time.Second)})
+
+	// the stored jobs are shared, the others arent.
+	if got, want := ids(a.GetAll()), "[1 2 3]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := ids(b.GetAll()), "[1 2]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := b.NextCycle(now), time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// a due job is claimed by a single scheduler.
+	nodes := a.RunNow(now.Add(time.Second))
+	if got, want := ids(nodes), "[1]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := ids(b.RunNow(now.Add(time.Second))), "[]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	a.Clean(now.Add(time.Second), nodes)
+	if got, want := ids(b.GetAll()), "[2]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// the claims of a scheduler which didnt clean expire.
+	nodes = a.RunNow(now.Add(time.Minute))
+	if got, want := ids(nodes), "[2 3]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := ids(b.RunNow(now.Add(time.Minute+30*time.Second))), "[]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// saving a claimed job keeps the claim.
+	if err := storeB.Save(StoredJob{Id: 2, Name: "store-test", Args: []string{"2"}, Schedule: ScheduleValue{EveryFixed(time.Minute)}, NextRun: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(b.RunNow(now.Add(time.Minute+30*time.Second))), "[]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	later := now.Add(2*time.Minute + time.Second)
+	taken := b.RunNow(later)
+	if got, want := ids(taken), "[2]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// cleaning a job claimed by another scheduler doesnt change it.
+	a.Clean(later, nodes)
+	b.Clean(later, taken)
+
+	all := b.GetAll()
+	if got, want := ids(all), "[2]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := all[0].NextRun, now.Add(3*time.Minute); !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	b.RemoveNode(2)
+	if got, want := ids(a.GetAll()), "[]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestSQLSchedulerUnregistered(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	store, _ := openFakeStore(t, WithOwner("a"), WithLease(time.Minute), WithPollInterval(10*time.Second))
+	s := store.Scheduler()
+
+	for _, job := range []StoredJob{
+		{Id: 1, Name: "sql-unregistered", Schedule: ScheduleValue{EveryFixed(time.Minute)}, NextRun: now.Add(-time.Minute)},
+		{Id: 2, Name: "store-test", Schedule: ScheduleValue{EveryFixed(time.Minute)}, NextRun: now.Add(time.Second)},
+	} {
+		if err := store.Save(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the jobs of functions which arent registered dont wake the scheduler up.
+	if got, want := s.NextCycle(now), time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := ids(s.RunNow(now.Add(time.Second))), "[2]"; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// nor are they claimed, leaving them to the processes registering them.
+	db := fakeDBs[t.Name()]
+	if got := db.jobs[1].claimedBy; got != "" {
+		t.Fatalf("got: %v want: no claim", got)
+	}
+}
+
+func TestSQLSchedulerPrecision(t *testing.T) {
+	store, _ := openFakeStore(t)
+	if err := store.Save(StoredJob{Id: 1, Name: "store-test", Schedule: ScheduleValue{Every(10 * time.Millisecond)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		confs     []CronJobConf
+		schedule  string
+		precision time.Duration
+	}{
+		// the stored jobs run with the precision of the cronjob reading them.
+		{nil, "@every 1s", defaultPrecision},
+		{[]CronJobConf{WithHighPrecision()}, "@every 10ms", highPrecision},
+	}
+
+	for i, c := range cases {
+		sched := store.Scheduler()
+		New(append(c.confs, WithScheduler(sched))...)
+
+		nodes := sched.GetAll()
+		if got, want := len(nodes), 1; got != want {
+			t.Fatalf("case %v: got: %v want: %v", i, got, want)
+		}
+		if got, want := scheduleText(nodes[0].Schedule), c.schedule; got != want {
+			t.Fatalf("case %v: got: %v want: %v", i, got, want)
+		}
+		if got, want := nodes[0].Job.precision, c.precision; got != want {
+			t.Fatalf("case %v: got: %v want: %v", i, got, want)
+		}
+
+		// the node read before is kept while the stored job doesnt change.
+		if got := sched.GetAll(); got[0] != nodes[0] {
+			t.Fatalf("case %v: got: new node want: node read before", i)
+		}
+	}
+}
+
+func TestSQLSchedulerCronJob(t *testing.T) {
+	store, open := openFakeStore(t, WithPollInterval(10*time.Millisecond))
+	c1 := New(WithScheduler(store.Scheduler()), WithHighPrecision())
+	c2 := New(WithScheduler(open(WithPollInterval(10*time.Millisecond)).Scheduler()), WithHighPrecision())
+
+	// the ids are shared by the cronjobs.
+	id1, err := c1.AddNamedFunc("store-test", In(c1.Now(), 50*time.Millisecond), "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id2 := c2.AddFunc(func() error { return nil }, Every(time.Hour))
+	if id1 == id2 {
+		t.Fatalf("got: %v want: an id other than %v", id2, id1)
+	}
+
+	// the job runs once, on one of the cronjobs.
+	c1.Start()
+	defer c1.Stop()
+	c2.Start()
+	defer c2.Stop()
+
+	select {
+	case args := <-ranArgs:
+		if args != "sql" {
+			t.Fatalf("got: %v want: sql", args)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no job ran.")
+	}
+
+	select {
+	case args := <-ranArgs:
+		t.Fatalf("got: %v want: no run", args)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
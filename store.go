@@ -0,0 +1,109 @@
+package cronjob
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Store keeps the jobs added with AddNamedFunc, so they survive restarts of the process,
+// see WithStore.
+//
+// the cronjob saves a job when it is added and each time its next activation changes,
+// deletes it once it has no further activations or is removed.
+type Store interface {
+	// Load returns all the jobs in the store.
+	Load() ([]StoredJob, error)
+
+	// Save adds the job or replaces the job with the same id.
+	Save(StoredJob) error
+
+	// Delete removes the job with the id provided, no-op if not found.
+	Delete(int) error
+}
+
+// StoredJob is a job kept by a store.
+type StoredJob struct {
+	// The id of the node of the job.
+	Id int `json:"id"`
+
+	// The name the function of the job is registered with, see RegisterJob.
+	Name string `json:"name"`
+
+	// The arguments the function of the job is called with.
+	Args []string `json:"args,omitempty"`
+
+	// The schedule of the job, written as text.
+	Schedule ScheduleValue `json:"schedule"`
+
+	// The time of the next activation of the job, the zero time until calculated.
+	NextRun time.Time `json:"next_run"`
+}
+
+var (
+	jobsMu sync.RWMutex
+
+	// registeredJobs maps the names of the registered functions to them.
+	registeredJobs = map[string]func(args ...string) error{}
+)
+
+// RegisterJob registers the function: job (field) under name (field), to add it with
+// AddNamedFunc and resolve the jobs loaded from a store.
+//
+// example:
+//
+//	cronjob.RegisterJob("send-reminder", func(args ...string) error {
+//		return mailer.Send(args[0], args[1])
+//	})
+//
+//	c := cronjob.New(cronjob.WithStore(store))
+//	c.AddNamedFunc("send-reminder", cronjob.At(at), "bob@example.com", "dentist")
+//
+// the functions should be registered before starting the cronjobs using them.
+//
+// panics if the name is empty or used by another function.
+func RegisterJob(name string, job func(args ...string) error) {
+	if name == "" {
+		panic("cronjob: invalid job name: \"\"")
+	}
+	if job == nil {
+		panic(fmt.Sprintf("cronjob: job %q is nil", name))
+	}
+
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	if _, ok := registeredJobs[name]; ok {
+		panic(fmt.Sprintf("cronjob: job %q registered twice", name))
+	}
+	registeredJobs[name] = job
+}
+
+// namedJob returns the job calling the function registered with name (field) with
+// args (field).
+func namedJob(name string, args []string) (*Job, error) {
+	jobsMu.RLock()
+	fn, ok := registeredJobs[name]
+	jobsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("cronjob: %q: job not registered", name)
+	}
+
+	return &Job{
+		job:  func() error { return fn(args...) },
+		name: name,
+		args: args,
+	}, nil
+}
+
+// storedJob returns the job of the node (field) as kept by a store.
+func storedJob(node *Node) StoredJob {
+	return StoredJob{
+		Id:       node.Id,
+		Name:     node.Job.name,
+		Args:     node.Job.args,
+		Schedule: ScheduleValue{node.Schedule},
+		NextRun:  node.NextRun,
+	}
+}
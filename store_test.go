@@ -0,0 +1,213 @@
+package cronjob
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ranArgs receives the arguments of the runs of the "store-test" job.
+var ranArgs = make(chan string, 100)
+
+func init() {
+	RegisterJob("store-test", func(args ...string) error {
+		ranArgs <- strings.Join(args, " ")
+		return nil
+	})
+}
+
+// memoryStore implements Store in memory.
+type memoryStore struct {
+	mu   sync.Mutex
+	jobs map[int]StoredJob
+}
+
+func newMemoryStore(jobs ...StoredJob) *memoryStore {
+	s := &memoryStore{jobs: make(map[int]StoredJob)}
+	for _, job := range jobs {
+		s.jobs[job.Id] = job
+	}
+	return s
+}
+
+func (s *memoryStore) Load() ([]StoredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobs []StoredJob
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Id < jobs[j].Id })
+	return jobs, nil
+}
+
+func (s *memoryStore) Save(job StoredJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.Id] = job
+	return nil
+}
+
+func (s *memoryStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
+
+// job returns the job with the id: id (field), reports whether its stored.
+func (s *memoryStore) job(id int) (StoredJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	return job, ok
+}
+
+func TestRegisterJob(t *testing.T) {
+	job := func(args ...string) error { return nil }
+
+	for _, name := range []string{"", "store-test"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%q: got: no panic want: panic", name)
+				}
+			}()
+
+			RegisterJob(name, job)
+		}()
+	}
+}
+
+func TestAddNamedFunc(t *testing.T) {
+	store := newMemoryStore()
+	c := New(WithStore(store))
+
+	if _, err := c.AddNamedFunc("unknown", Every(time.Hour)); err == nil {
+		t.Fatal("got: nil want: error")
+	}
+	if _, err := c.AddNamedFunc("store-test", OnChannel(make(chan int))); err == nil {
+		t.Fatal("got: nil want: error")
+	}
+
+	at := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	id, err := c.AddNamedFunc("store-test", At(at), "bob", "dentist")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, ok := store.job(id)
+	if !ok {
+		t.Fatalf("got: no job want: job with id %v", id)
+	}
+	text, _ := job.Schedule.MarshalText()
+	if got, want := []interface{}{job.Name, job.Args, string(text)}, []interface{}{"store-test", []string{"bob", "dentist"}, scheduleText(At(at))}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := job.NextRun, at; !got.Equal(want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// jobs added with AddFunc arent stored.
+	c.AddFunc(func() error { return nil }, Every(time.Hour))
+	if got, want := len(store.jobs), 1; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	c.RemoveJob(id)
+	if _, ok := store.job(id); ok {
+		t.Fatal("got: job want: no job")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	now := time.Now()
+
+	store := newMemoryStore(
+		// ran while stopped.
+		StoredJob{Id: 3, Name: "store-test", Args: []string{"at"}, Schedule: ScheduleValue{At(now.Add(-time.Minute))}},
+		// missed an activation while stopped.
+		StoredJob{Id: 7, Name: "store-test", Args: []string{"every"}, Schedule: ScheduleValue{EveryFixed(time.Hour)}, NextRun: now.Add(-30 * time.Minute)},
+		// not registered by this process.
+		StoredJob{Id: 9, Name: "unknown", Schedule: ScheduleValue{EveryFixed(time.Hour)}},
+	)
+
+	c := New(WithStore(store))
+
+	// new jobs dont take the ids of the stored ones.
+	id, err := c.AddNamedFunc("store-test", Every(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= 9 {
+		t.Fatalf("got: %v want: an id after 9", id)
+	}
+
+	c.Start()
+	defer c.Stop()
+
+	ran := make(map[string]bool)
+	for len(ran) < 2 {
+		select {
+		case args := <-ranArgs:
+			ran[args] = true
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got: %v want: at and every ran", ran)
+		}
+	}
+
+	// sync with the processing thread, the one-shot job left the store.
+	c.Jobs()
+	if _, ok := store.job(3); ok {
+		t.Fatal("got: job 3 want: no job")
+	}
+
+	job, ok := store.job(7)
+	if !ok || !job.NextRun.After(now) {
+		t.Fatalf("got: %v want: job 7 running after %v", job, now)
+	}
+	if _, ok := store.job(9); !ok {
+		t.Fatal("got: no job 9 want: job 9")
+	}
+
+	// the run of the missed activation of job 1 doesnt take the id of job 2.
+	store = newMemoryStore(
+		StoredJob{Id: 1, Name: "store-test", Args: []string{"missed"}, Schedule: ScheduleValue{EveryFixed(time.Hour)}, NextRun: now.Add(-30 * time.Minute)},
+		StoredJob{Id: 2, Name: "store-test", Args: []string{"next"}, Schedule: ScheduleValue{EveryFixed(time.Hour)}},
+	)
+
+	c = New(WithStore(store))
+	c.RemoveJob(42)
+
+	ids := make(map[int]bool)
+	for _, node := range c.Jobs() {
+		if ids[node.Id] {
+			t.Fatalf("got: id %v twice want: unique ids", node.Id)
+		}
+		ids[node.Id] = true
+	}
+	if got, want := len(ids), 3; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// the run of the missed activation runs the stored job.
+	for _, node := range c.Jobs() {
+		if _, ok := node.Schedule.(*constantSchedule); !ok {
+			continue
+		}
+
+		if got, want := node.Job.name+" "+strings.Join(node.Job.args, " "), "store-test missed"; got != want {
+			t.Fatalf("got: %v want: %v", got, want)
+		}
+		if got, want := node.Job.precision, defaultPrecision; got != want {
+			t.Fatalf("got: %v want: %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,478 @@
+package cronjob
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// systemdWeekdayBounds are the bounds of the systemd weekday field, the week starts on
+// monday.
+var systemdWeekdayBounds = bounds{1, 7, map[string]uint{
+	"mon":       1,
+	"tue":       2,
+	"wed":       3,
+	"thu":       4,
+	"fri":       5,
+	"sat":       6,
+	"sun":       7,
+	"monday":    1,
+	"tuesday":   2,
+	"wednesday": 3,
+	"thursday":  4,
+	"friday":    5,
+	"saturday":  6,
+	"sunday":    7,
+}}
+
+// systemdYearBounds are the years supported by systemd.
+var systemdYearBounds = struct{ min, max int }{1970, 2199}
+
+// systemdShorthands maps the systemd calendar shorthands to their expressions.
+var systemdShorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+}
+
+// ParseSystemdCalendar parses a systemd calendar event expression (OnCalendar=) and
+// returns a schedule which runs at the times matched by it.
+//
+// the expression has the form:
+//
+//	[weekdays] [[year-]month-day] [hour:minute[:second]] [timezone]
+//
+// each component can be a value, a comma separated list, a range (a..b), a repetition
+// (a/step or a..b/step) or *. the day can be written after a ~ instead of a - to count
+// from the last day of the month:
+//
+//	Mon..Fri *-*-* 08:00:00    (weekdays at 08:00)
+//	*-*-01 00:00:00 Europe/Berlin    (first day of the month at midnight in berlin)
+//	*-02~01 12:00    (last day of february at 12:00)
+//	Mon *-05~07/1    (last monday of may at midnight)
+//	*:0/15    (every 15 minutes)
+//
+// the date defaults to *-*-*, the time to 00:00:00 and the timezone to the location used
+// by the cronjob. the shorthands minutely, hourly, daily, weekly, monthly, quarterly,
+// semiannually, yearly and annually are accepted. an OnCalendar= prefix is ignored.
+//
+// unlike cron expressions, the weekdays and the date both need to match.
+func ParseSystemdCalendar(expr string) (CyclicSchedule, error) {
+	source := strings.TrimPrefix(strings.TrimSpace(expr), "OnCalendar=")
+
+	sched, err := parseSystemdCalendar(source)
+	if err != nil {
+		return nil, fmt.Errorf("cronjob: %q: %w", expr, err)
+	}
+	sched.source = source
+
+	return sched, nil
+}
+
+func parseSystemdCalendar(expr string) (*systemdSchedule, error) {
+	tokens := strings.Fields(expr)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	var (
+		sched = &systemdSchedule{}
+		err   error
+	)
+
+	// timezone, the only component which starts with a letter apart from the weekdays
+	// and the shorthands.
+	if last := tokens[len(tokens)-1]; len(tokens) > 1 && isLetter(last[0]) && !isSystemdWeekdays(last) {
+		if sched.location, err = time.LoadLocation(last); err != nil {
+			return nil, fmt.Errorf("failed to load location %q: %w", last, err)
+		}
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	if len(tokens) == 1 {
+		if shorthand, ok := systemdShorthands[strings.ToLower(tokens[0])]; ok {
+			tokens = strings.Fields(shorthand)
+		}
+	}
+
+	// weekdays.
+	sched.weekday = bitRange(0, 6, 1)
+	if isLetter(tokens[0][0]) {
+		if sched.weekday, err = parseField(strings.ReplaceAll(tokens[0], "..", "-"), systemdWeekdayBounds); err != nil {
+			return nil, err
+		}
+		sched.weekday = foldSunday(sched.weekday)
+		tokens = tokens[1:]
+	}
+
+	date, clock := "*-*-*", "00:00:00"
+	switch {
+	case len(tokens) == 2:
+		date, clock = tokens[0], tokens[1]
+	case len(tokens) == 1 && strings.Contains(tokens[0], ":"):
+		clock = tokens[0]
+	case len(tokens) == 1:
+		date = tokens[0]
+	case len(tokens) > 2:
+		return nil, fmt.Errorf("too many components")
+	}
+
+	if strings.Contains(date, ":") || !strings.Contains(clock, ":") {
+		return nil, fmt.Errorf("expected [weekdays] [date] [time] [timezone]")
+	}
+
+	if err := sched.parseDate(date); err != nil {
+		return nil, err
+	}
+	if err := sched.parseClock(clock); err != nil {
+		return nil, err
+	}
+
+	return sched, nil
+}
+
+// isSystemdWeekdays reports whether token (field) is a systemd weekday component.
+func isSystemdWeekdays(token string) bool {
+	_, err := parseField(strings.ReplaceAll(token, "..", "-"), systemdWeekdayBounds)
+	return err == nil
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// yearRange is a range of years, a..b/step.
+type yearRange struct {
+	start, end, step int
+}
+
+// SystemdSchedule --------------------------------------------------------------
+
+type systemdSchedule struct {
+	// years restrict the years the schedule runs in, nil matches all years.
+	years []yearRange
+
+	// each field is a set of bits, the bit at position n is set if the field matches n.
+	month, day, weekday, hour, minute, second uint64
+
+	// lastDays has the bit at position n set for the n-th day before the last day of
+	// the month (~n+1).
+	lastDays uint64
+
+	// source is the calendar event the schedule was parsed from.
+	source string
+
+	// location is the location the schedule is evaluated in, if nil the location of
+	// the time passed to the schedule is used.
+	location *time.Location
+
+	// dst is the policy used for wall clock times skipped by daylight saving time.
+	dst DSTPolicy
+
+	// nextActivation will hold the time of the next activation.
+	nextActivation time.Time
+}
+
+func (s *systemdSchedule) Calculate(now time.Time) time.Duration {
+	if s.nextActivation.IsZero() {
+		return never
+	}
+
+	return s.nextActivation.Sub(now)
+}
+
+func (s *systemdSchedule) MoveNextAvtivation(now time.Time) {
+	s.nextActivation = s.Next(now)
+}
+
+func (s *systemdSchedule) withDSTPolicy(policy DSTPolicy) (Schedule, bool) {
+	if s.dst == policy {
+		return s, false
+	}
+
+	set := *s
+	set.dst = policy
+	return &set, true
+}
+
+// String returns the @systemd descriptor of the calendar event.
+func (s *systemdSchedule) String() string {
+	return withDST(s.dst, "@systemd "+s.source)
+}
+
+func (s *systemdSchedule) MarshalText() ([]byte, error) {
+	return marshalText(s)
+}
+
+func (s *systemdSchedule) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+func (s *systemdSchedule) UnmarshalText(text []byte) error {
+	return unmarshalText(s, text)
+}
+
+// Next returns the first time matched by the schedule after: after (field), in the
+// location of the schedule or the location of after (field) if the schedule has none.
+//
+// returns the zero time if no time matches in the search window.
+func (s *systemdSchedule) Next(after time.Time) time.Time {
+	if s.location != nil {
+		after = after.In(s.location)
+	}
+	loc := after.Location()
+
+	// activations happen on whole seconds, start looking from the next one.
+	t := after.Truncate(time.Second).Add(time.Second)
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < searchDays; i, date = i+1, date.AddDate(0, 0, 1) {
+		if i > 0 {
+			hour, min, sec = 0, 0, 0
+		}
+
+		// jump to the next year matched by the schedule.
+		year, ok := s.nextYear(date.Year())
+		if !ok {
+			return time.Time{}
+		}
+		if year != date.Year() {
+			date = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+			hour, min, sec = 0, 0, 0
+		}
+
+		if !s.matchDay(date) {
+			continue
+		}
+
+		for h, m, sc, ok := s.clock(hour, min, sec); ok; h, m, sc, ok = s.clock(h, m, sc+1) {
+			wall := time.Date(date.Year(), date.Month(), date.Day(), h, m, sc, 0, time.UTC)
+			if t, ok := wallClock(wall, loc, s.dst); ok && t.After(after) {
+				return t
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// clock returns the earliest time of day matched by the schedule which isnt before
+// hour:min:sec.
+func (s *systemdSchedule) clock(hour, min, sec int) (int, int, int, bool) {
+	return nextClock(s.hour, s.minute, s.second, hour, min, sec)
+}
+
+// nextYear returns the first year matched by the schedule which isnt before year (field).
+func (s *systemdSchedule) nextYear(year int) (int, bool) {
+	if s.years == nil {
+		return year, true
+	}
+
+	next, found := 0, false
+	for _, r := range s.years {
+		y := r.start
+		if year > y {
+			// round up to the next step of the range.
+			y += (year - y + r.step - 1) / r.step * r.step
+		}
+
+		if y <= r.end && (!found || y < next) {
+			next, found = y, true
+		}
+	}
+
+	return next, found
+}
+
+// matchDay reports whether the schedule runs on the day of date (field).
+func (s *systemdSchedule) matchDay(date time.Time) bool {
+	if s.month&(1<<uint(date.Month())) == 0 || s.weekday&(1<<uint(date.Weekday())) == 0 {
+		return false
+	}
+
+	return s.day&(1<<uint(date.Day())) > 0 ||
+		s.lastDays&(1<<uint(daysIn(date.Year(), date.Month())-date.Day())) > 0
+}
+
+// parseDate parses the [year-]month-day component.
+func (s *systemdSchedule) parseDate(date string) error {
+	i := strings.LastIndexAny(date, "-~")
+	if i == -1 {
+		return fmt.Errorf("expected [year-]month-day: %q", date)
+	}
+
+	parts := strings.Split(date[:i], "-")
+	switch len(parts) {
+	case 1:
+	case 2:
+		years, err := parseSystemdYears(parts[0])
+		if err != nil {
+			return err
+		}
+		s.years = years
+		parts = parts[1:]
+	default:
+		return fmt.Errorf("expected [year-]month-day: %q", date)
+	}
+
+	var err error
+	if s.month, err = parseField(strings.ReplaceAll(parts[0], "..", "-"), monthBounds); err != nil {
+		return err
+	}
+
+	if date[i] == '~' {
+		s.lastDays, err = parseSystemdLastDays(date[i+1:])
+	} else {
+		s.day, err = parseField(strings.ReplaceAll(date[i+1:], "..", "-"), domBounds)
+	}
+
+	return err
+}
+
+// parseClock parses the hour:minute[:second] component.
+func (s *systemdSchedule) parseClock(clock string) error {
+	parts := strings.Split(clock, ":")
+	switch len(parts) {
+	case 2:
+		parts = append(parts, "00")
+	case 3:
+	default:
+		return fmt.Errorf("expected hour:minute[:second]: %q", clock)
+	}
+
+	var err error
+	field := func(i int, b bounds) uint64 {
+		if err != nil {
+			return 0
+		}
+
+		var bits uint64
+		bits, err = parseField(strings.ReplaceAll(parts[i], "..", "-"), b)
+		return bits
+	}
+
+	s.hour = field(0, hourBounds)
+	s.minute = field(1, minuteBounds)
+	s.second = field(2, secondBounds)
+
+	return err
+}
+
+// parseSystemdYears parses the year component, nil is returned for *.
+func parseSystemdYears(field string) ([]yearRange, error) {
+	if field == "*" {
+		return nil, nil
+	}
+
+	var years []yearRange
+	for _, expr := range strings.Split(field, ",") {
+		rangeAndStep := strings.Split(expr, "/")
+		lowAndHigh := strings.Split(rangeAndStep[0], "..")
+
+		var (
+			r   = yearRange{end: systemdYearBounds.max, step: 1}
+			err error
+		)
+
+		if lowAndHigh[0] == "*" {
+			r.start = systemdYearBounds.min
+		} else if r.start, err = parseSystemdYear(lowAndHigh[0]); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case len(lowAndHigh) == 2 && lowAndHigh[0] != "*":
+			if r.end, err = parseSystemdYear(lowAndHigh[1]); err != nil {
+				return nil, err
+			}
+		case len(lowAndHigh) != 1:
+			return nil, fmt.Errorf("invalid range: %q", expr)
+
+		// a single year only repeats with a step.
+		case len(rangeAndStep) == 1 && lowAndHigh[0] != "*":
+			r.end = r.start
+		}
+
+		switch len(rangeAndStep) {
+		case 1:
+		case 2:
+			step, err := strconv.Atoi(rangeAndStep[1])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("step of range should be a positive number: %q", expr)
+			}
+			r.step = step
+		default:
+			return nil, fmt.Errorf("too many slashes: %q", expr)
+		}
+
+		if r.start > r.end {
+			return nil, fmt.Errorf("beginning of range (%v) beyond end of range (%v): %q", r.start, r.end, expr)
+		}
+
+		years = append(years, r)
+	}
+
+	return years, nil
+}
+
+func parseSystemdYear(expr string) (int, error) {
+	year, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse number %q", expr)
+	}
+
+	if year < systemdYearBounds.min || year > systemdYearBounds.max {
+		return 0, fmt.Errorf("year (%v) outside of %v..%v", year, systemdYearBounds.min, systemdYearBounds.max)
+	}
+
+	return year, nil
+}
+
+// parseSystemdLastDays parses the day component written after a ~, counting from the last
+// day of the month.
+func parseSystemdLastDays(field string) (uint64, error) {
+	var bits uint64
+	for _, expr := range strings.Split(field, ",") {
+		// "N/step" counts down towards the last day of the month.
+		if i := strings.Index(expr, "/"); i != -1 && !strings.Contains(expr, "..") && expr[:i] != "*" {
+			start, err := parseValue(expr[:i], domBounds)
+			if err != nil {
+				return 0, err
+			}
+
+			step, err := parseUint(expr[i+1:])
+			switch {
+			case err != nil:
+				return 0, err
+			case start < domBounds.min || start > domBounds.max:
+				return 0, fmt.Errorf("day (%v) outside of %v..%v: %q", start, domBounds.min, domBounds.max, expr)
+			case step == 0:
+				return 0, fmt.Errorf("step of range should be a positive number: %q", expr)
+			}
+
+			for d := int(start); d >= 1; d -= int(step) {
+				bits |= 1 << uint(d-1)
+			}
+			continue
+		}
+
+		days, err := parseRange(strings.ReplaceAll(expr, "..", "-"), domBounds)
+		if err != nil {
+			return 0, err
+		}
+
+		// ~1 is the last day, at position 0.
+		bits |= days &^ starBit >> 1
+	}
+
+	return bits, nil
+}
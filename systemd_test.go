@@ -0,0 +1,91 @@
+package cronjob
+
+import (
+	"testing"
+)
+
+func TestSystemdCalendarNext(t *testing.T) {
+	// a saturday.
+	const after = "2026-10-17T10:05:00Z"
+
+	cases := []struct {
+		expr     string
+		after    string
+		expected string
+	}{
+		{"Mon..Fri *-*-* 08:00:00", after, "2026-10-19T08:00:00Z"},
+		{"OnCalendar=Mon..Fri *-*-* 08:00:00", after, "2026-10-19T08:00:00Z"},
+		{"Monday 9:30", after, "2026-10-19T09:30:00Z"},
+		{"Sat,Sun 10:00", after, "2026-10-18T10:00:00Z"},
+		{"Sat..Sun *-10-* 10:00", after, "2026-10-18T10:00:00Z"},
+		{"Fri *-*-13", after, "2026-11-13T00:00:00Z"},
+		{"*-*-* 10:05:00", after, "2026-10-18T10:05:00Z"},
+		{"*-*-1..7/2 06:00", after, "2026-11-01T06:00:00Z"},
+		{"*:0/15", after, "2026-10-17T10:15:00Z"},
+
+		// timezone.
+		{"*-*-01 00:00:00 Europe/Berlin", after, "2026-11-01T00:00:00+01:00"},
+		{"*-*-* 02:30 America/New_York", "2026-03-08T00:00:00-05:00", "2026-03-08T03:00:00-04:00"},
+
+		// days counted from the end of the month.
+		{"*-02~01 12:00", after, "2027-02-28T12:00:00Z"},
+		{"*-*~03", after, "2026-10-29T00:00:00Z"},
+		{"Mon *-05~07/1", after, "2027-05-31T00:00:00Z"},
+
+		// years.
+		{"2030-*-* 00:00", after, "2030-01-01T00:00:00Z"},
+		{"2026/2-01-01", after, "2028-01-01T00:00:00Z"},
+		{"2027..2029,2035-06-15 12:00", after, "2027-06-15T12:00:00Z"},
+		{"2020..2025-*-*", after, ""},
+
+		// shorthands.
+		{"minutely", after, "2026-10-17T10:06:00Z"},
+		{"hourly", after, "2026-10-17T11:00:00Z"},
+		{"daily", after, "2026-10-18T00:00:00Z"},
+		{"weekly", after, "2026-10-19T00:00:00Z"},
+		{"monthly", after, "2026-11-01T00:00:00Z"},
+		{"quarterly", after, "2027-01-01T00:00:00Z"},
+		{"semiannually", after, "2027-01-01T00:00:00Z"},
+		{"yearly", after, "2027-01-01T00:00:00Z"},
+		{"daily Asia/Tokyo", after, "2026-10-18T00:00:00+09:00"},
+
+		// never matched.
+		{"*-02-30", after, ""},
+	}
+
+	for _, c := range cases {
+		sched, err := ParseSystemdCalendar(c.expr)
+		if err != nil {
+			t.Fatalf("%q: %v", c.expr, err)
+		}
+
+		got := sched.(*systemdSchedule).Next(mustParseTime(t, c.after))
+		if want := mustParseTime(t, c.expected); !got.Equal(want) {
+			t.Fatalf("%q: got: %v want: %v", c.expr, got, want)
+		}
+	}
+}
+
+func TestParseSystemdCalendarErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"Funday",
+		"Mon..Fri Mars/Olympus",
+		"*-*-* 25:00",
+		"*-13-01",
+		"*-*-32",
+		"1969-01-01",
+		"2026..2020-01-01",
+		"*-*-*/0",
+		"*-*~0",
+		"*-*~7/0",
+		"*-*-* 08:00 09:00",
+		"08:00 *-*-*",
+		"*:*:*:*",
+		"2026-01",
+	} {
+		if _, err := ParseSystemdCalendar(expr); err == nil {
+			t.Fatalf("%q: expected error", expr)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package cronjob
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+)
+
+// TriggerConf represents a function to configure the behaviour of a trigger schedule.
+type TriggerConf func(*triggerSchedule)
+
+// WithDebounce delays the runs of a trigger schedule until no event fired for
+// debounce (field), a burst of events runs the job once.
+func WithDebounce(debounce time.Duration) TriggerConf {
+	return func(s *triggerSchedule) {
+		s.debounce = debounce
+	}
+}
+
+// WithThrottle runs the job of a trigger schedule at most once every throttle (field),
+// events fired in between run the job once at the end of the interval.
+func WithThrottle(throttle time.Duration) TriggerConf {
+	return func(s *triggerSchedule) {
+		s.throttle = throttle
+	}
+}
+
+// OnChannel returns a schedule which runs when a value is received on ch (field), a
+// channel of any type the cronjob can receive from.
+//
+// example:
+//
+//	uploads := make(chan string)
+//	(*CronJob).AddFunc(reindex, cronjob.OnChannel(uploads, cronjob.WithThrottle(10 * time.Second)))
+//
+// reindex runs when a value arrives on uploads, at most once every 10 seconds.
+//
+// the values are received while the cronjob is running, the schedule stops when the
+// channel is closed. panics if ch (field) isnt a channel.
+func OnChannel(ch interface{}, confs ...TriggerConf) Schedule {
+	v := reflect.ValueOf(ch)
+	if v.Kind() != reflect.Chan || v.Type().ChanDir()&reflect.RecvDir == 0 {
+		panic(fmt.Sprintf("cronjob: cant receive from %T", ch))
+	}
+
+	return newTrigger(func() func(<-chan struct{}, func()) {
+		return func(stop <-chan struct{}, fire func()) {
+			cases := []reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: v},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop)},
+			}
+
+			for {
+				if chosen, _, ok := reflect.Select(cases); chosen == 1 || !ok {
+					return
+				}
+				fire()
+			}
+		}
+	}, confs...)
+}
+
+// OnPredicate returns a schedule which runs when predicate (field) reports true,
+// checked every poll (field).
+//
+// the predicate is called from its own goroutine while the cronjob is running.
+func OnPredicate(poll time.Duration, predicate func() bool, confs ...TriggerConf) Schedule {
+	return onPredicate(poll, func() func() bool { return predicate }, confs...)
+}
+
+// onPredicate is OnPredicate with a predicate returned by newPredicate (field) for each
+// job, predicates holding state dont race when the schedule is shared.
+func onPredicate(poll time.Duration, newPredicate func() func() bool, confs ...TriggerConf) *triggerSchedule {
+	if poll < highPrecision {
+		poll = highPrecision
+	}
+
+	return newTrigger(func() func(<-chan struct{}, func()) {
+		predicate := newPredicate()
+
+		return func(stop <-chan struct{}, fire func()) {
+			ticker := time.NewTicker(poll)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					if predicate() {
+						fire()
+					}
+				case <-stop:
+					return
+				}
+			}
+		}
+	}, confs...)
+}
+
+// OnFileChange returns a schedule which runs when the modification time of the file at
+// path (field) changes, checked every poll (field). creating and removing the file are
+// changes too.
+func OnFileChange(path string, poll time.Duration, confs ...TriggerConf) Schedule {
+	added := fileModTime(path)
+
+	// each job compares to the modification time it last saw.
+	return onPredicate(poll, func() func() bool {
+		modTime := added
+
+		return func() bool {
+			t := fileModTime(path)
+			if t.Equal(modTime) {
+				return false
+			}
+
+			modTime = t
+			return true
+		}
+	}, confs...)
+}
+
+// fileModTime returns the modification time of the file at path (field), the zero time
+// if it doesnt exist.
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// newTrigger returns a trigger schedule fired by the watches returned by newWatch (field).
+func newTrigger(newWatch func() func(stop <-chan struct{}, fire func()), confs ...TriggerConf) *triggerSchedule {
+	sched := &triggerSchedule{
+		newWatch: newWatch,
+	}
+
+	for _, conf := range confs {
+		conf(sched)
+	}
+	return sched
+}
+
+// TriggerSchedule ------------------------------------------------------------------
+
+// triggerSchedule runs when its events fire, the cronjob watches the events while running
+// and adds the job to the scheduler when they fire.
+//
+// the cronjob keeps a copy of the schedule for each job (see forJob), it can be shared.
+type triggerSchedule struct {
+	// newWatch returns the watch of a job.
+	newWatch func() func(stop <-chan struct{}, fire func())
+
+	// watch calls fire for each event until stop is closed.
+	watch func(stop <-chan struct{}, fire func())
+
+	debounce, throttle time.Duration
+
+	// pending will hold the time of the run of the fired events, zero if none fired.
+	pending time.Time
+
+	// lastRun will hold the time the job last ran.
+	lastRun time.Time
+}
+
+func (s *triggerSchedule) Calculate(now time.Time) time.Duration {
+	if s.pending.IsZero() {
+		return never
+	}
+
+	return s.pending.Sub(now)
+}
+
+// forJob returns a copy of the schedule with its own watch and without events fired.
+func (s *triggerSchedule) forJob() Schedule {
+	return &triggerSchedule{
+		newWatch: s.newWatch,
+		watch:    s.newWatch(),
+		debounce: s.debounce,
+		throttle: s.throttle,
+	}
+}
+
+// fire records an event fired at now (field).
+func (s *triggerSchedule) fire(now time.Time) {
+	if s.pending.IsZero() || s.debounce > 0 {
+		s.pending = now.Add(s.debounce)
+	}
+
+	if earliest := s.lastRun.Add(s.throttle); s.throttle > 0 && s.pending.Before(earliest) {
+		s.pending = earliest
+	}
+}
+
+// ran records the run of the job at now (field).
+func (s *triggerSchedule) ran(now time.Time) {
+	s.lastRun = now
+	s.pending = time.Time{}
+}
@@ -0,0 +1,173 @@
+package cronjob
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTriggerFire(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		confs    []TriggerConf
+		lastRun  time.Time
+		events   []time.Duration
+		expected time.Duration
+	}{
+		// runs at the first event.
+		{nil, time.Time{}, []time.Duration{0, time.Second}, 0},
+
+		// runs after the last event of the burst.
+		{[]TriggerConf{WithDebounce(5 * time.Second)}, time.Time{}, []time.Duration{0, time.Second, 3 * time.Second}, 8 * time.Second},
+
+		// runs once the interval since the last run passed.
+		{[]TriggerConf{WithThrottle(10 * time.Second)}, now.Add(-4 * time.Second), []time.Duration{0, time.Second}, 6 * time.Second},
+		{[]TriggerConf{WithThrottle(10 * time.Second)}, now.Add(-time.Minute), []time.Duration{0, time.Second}, 0},
+
+		{[]TriggerConf{WithDebounce(time.Second), WithThrottle(10 * time.Second)}, now.Add(-9 * time.Second), []time.Duration{0}, time.Second},
+		{[]TriggerConf{WithDebounce(5 * time.Second), WithThrottle(10 * time.Second)}, now.Add(-9 * time.Second), []time.Duration{0}, 5 * time.Second},
+	}
+
+	for i, c := range cases {
+		sched := newTrigger(nil, c.confs...)
+		sched.lastRun = c.lastRun
+
+		if got := sched.Calculate(now); got != never {
+			t.Fatalf("case %v: got: %v want: %v", i, got, never)
+		}
+
+		for _, event := range c.events {
+			sched.fire(now.Add(event))
+		}
+
+		if got := sched.Calculate(now); got != c.expected {
+			t.Fatalf("case %v: got: %v want: %v", i, got, c.expected)
+		}
+
+		sched.ran(now)
+		if got := sched.Calculate(now); got != never {
+			t.Fatalf("case %v: got: %v want: %v", i, got, never)
+		}
+	}
+}
+
+func TestOnChannel(t *testing.T) {
+	t.Parallel()
+	wg := &sync.WaitGroup{}
+	wg.Add(3)
+
+	events := make(chan string)
+
+	c := New()
+	id := c.AddFunc(func() error { wg.Done(); return nil }, OnChannel(events))
+	c.Start()
+	defer c.Stop()
+
+	// the job waiting for events is listed.
+	if got, want := len(c.Jobs()), 1; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	for i := 0; i < 3; i++ {
+		events <- "upload"
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	select {
+	case <-wait(wg):
+		// jobs ran.
+	case <-time.After(2 * time.Second):
+		t.Fatal("no job ran.")
+	}
+
+	// the events arent received once the job is removed.
+	c.RemoveJob(id)
+	if got, want := len(c.Jobs()), 0; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	select {
+	case events <- "upload":
+		t.Fatal("event received.")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestOnChannelDebounce(t *testing.T) {
+	t.Parallel()
+	var count int32
+
+	events := make(chan struct{}, 10)
+
+	c := New()
+	c.Start()
+	defer c.Stop()
+	c.AddFunc(func() error { atomic.AddInt32(&count, 1); return nil }, OnChannel(events, WithDebounce(200*time.Millisecond)))
+
+	for i := 0; i < 5; i++ {
+		events <- struct{}{}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	time.Sleep(time.Second)
+	if got, want := atomic.LoadInt32(&count), int32(1); got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestOnFileChange(t *testing.T) {
+	t.Parallel()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	c := New()
+	c.AddFunc(func() error { wg.Done(); return nil }, OnFileChange(path, 10*time.Millisecond))
+	c.Start()
+	defer c.Stop()
+
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-wait(wg):
+		// job ran.
+	case <-time.After(2 * time.Second):
+		t.Fatal("no job ran.")
+	}
+}
+
+func TestOnFileChangeShared(t *testing.T) {
+	t.Parallel()
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	sched := OnFileChange(path, 10*time.Millisecond, WithThrottle(time.Minute))
+
+	// each job sees the change and keeps its own throttle.
+	var runs int32
+	c := New()
+	for i := 0; i < 2; i++ {
+		c.AddFunc(func() error { atomic.AddInt32(&runs, 1); wg.Done(); return nil }, sched)
+	}
+	c.Start()
+	defer c.Stop()
+
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-wait(wg):
+		// jobs ran.
+	case <-time.After(2 * time.Second):
+		t.Fatalf("got: %v want: %v", atomic.LoadInt32(&runs), 2)
+	}
+}
@@ -0,0 +1,360 @@
+package cronjob
+
+import (
+	"math/bits"
+	"time"
+)
+
+// wheelBits is the number of bits of the ticks indexing the slots of a wheel.
+const wheelBits = 6
+
+// wheelSize is the number of slots of a wheel.
+const wheelSize = 1 << wheelBits
+
+// wheelMask masks the bits of the ticks indexing the slots of a wheel.
+const wheelMask = wheelSize - 1
+
+// NewWheelScheduler returns a scheduler keeping the nodes in hierarchical timing wheels
+// of 64 slots, the first wheel turning a slot every tick (field), each next wheel 64
+// times slower. wheels are added as far-future nodes need them.
+//
+// adding and removing a node takes O(1), without allocating besides the index of the
+// nodes by id. use it with WithScheduler for cronjobs with millions of jobs:
+//
+//	cronjob.New(cronjob.WithScheduler(cronjob.NewWheelScheduler(time.Second)))
+//
+// the nodes run at their exact activation, the tick is the granularity the wheels move
+// by: a shorter tick wakes the cronjob up more often to move them. ticks which arent
+// positive are raised to a second.
+//
+// the ids of the nodes must be unique, a node added with the id of another replaces it.
+func NewWheelScheduler(tick time.Duration) Scheduler {
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	return &wheelScheduler{
+		tick:   tick,
+		wheels: []*wheel{{}},
+		index:  make(map[int]*Node),
+	}
+}
+
+// wheel is a ring of slots, each slot holds a list of the nodes activated in it.
+type wheel struct {
+	slots [wheelSize]*Node
+
+	// occupied has the bit at position n set if the slot n might hold nodes, it is
+	// cleared once the slot is found empty.
+	occupied uint64
+}
+
+type wheelScheduler struct {
+	// tick is the duration of a slot of the first wheel.
+	tick time.Duration
+
+	// origin is the time of the tick 0, set by the first node added.
+	origin time.Time
+
+	// current is the first tick which wasnt moved past yet.
+	current uint64
+
+	// wheels[k] holds the nodes activated in the current turn of wheels[k+1] (field),
+	// but not in the current slot of wheels[k], in its slot of 64^k ticks.
+	wheels []*wheel
+
+	// due holds the nodes of the ticks moved past, which might still be after now.
+	due *Node
+
+	// index maps the ids of the nodes to them.
+	index map[int]*Node
+}
+
+// NextCycle gets the duration of sleeping before activating.
+//
+// the duration of nodes in the further wheels is the start of their slot, moving the
+// wheels wakes the cronjob up before they run.
+func (w *wheelScheduler) NextCycle(now time.Time) time.Duration {
+	if len(w.index) == 0 {
+		return -1
+	}
+
+	// the nodes in due (field) are before the ones in the wheels.
+	next := earliest(w.due)
+	if next.IsZero() {
+		tick, level, ok := w.nextSlot()
+		if !ok {
+			return -1
+		}
+
+		if level == 0 {
+			next = earliest(w.wheels[0].slots[tick&wheelMask])
+		} else {
+			next = w.origin.Add(time.Duration(tick) * w.tick)
+		}
+	}
+
+	if v := next.Sub(now); v > 0 {
+		return v
+	}
+	return 0
+}
+
+// RunNow gets all the nodes scheduled to run now or in the past, in the order of their
+// activations.
+func (w *wheelScheduler) RunNow(now time.Time) (nodes []*Node) {
+	if len(w.index) == 0 {
+		return
+	}
+
+	if tick := w.tickOf(now); tick >= 0 {
+		w.advance(uint64(tick))
+	}
+
+	for node := w.due; node != nil; node = node.next {
+		if !node.NextRun.After(now) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	sortNodes(nodes)
+	return
+}
+
+// GetAll returns all the nodes in the order of their activations.
+func (w *wheelScheduler) GetAll() []*Node {
+	nodes := make([]*Node, 0, len(w.index))
+	for _, node := range w.index {
+		nodes = append(nodes, node)
+	}
+
+	sortNodes(nodes)
+	return nodes
+}
+
+// AddNode adds the node (field) in the wheels based on its next activation.
+//
+// no-op if node is nil or its schedule has no further activations.
+func (w *wheelScheduler) AddNode(now time.Time, node *Node) {
+	if node == nil {
+		return
+	}
+
+	node.NextRun = nextRun(now, node.Schedule)
+	if node.NextRun.IsZero() {
+		return
+	}
+
+	if w.origin.IsZero() {
+		w.origin = now.Truncate(w.tick)
+	}
+
+	w.RemoveNode(node.Id)
+	w.index[node.Id] = node
+	w.place(node)
+}
+
+// RemoveNode removes the node with the given id.
+//
+// no-op if node not found.
+func (w *wheelScheduler) RemoveNode(id int) {
+	if node, ok := w.index[id]; ok {
+		unlink(node)
+		delete(w.index, id)
+	}
+}
+
+// Clean removes the nodes (field) and re-calculates the cyclic ones.
+func (w *wheelScheduler) Clean(now time.Time, nodes []*Node) {
+	for _, node := range nodes {
+		if w.index[node.Id] != node {
+			continue
+		}
+		unlink(node)
+
+		if _, cyclic := node.Schedule.(CyclicSchedule); cyclic {
+			if next := rescheduled(now, node); !next.IsZero() {
+				node.NextRun = next
+				w.place(node)
+				continue
+			}
+		}
+
+		delete(w.index, node.Id)
+	}
+}
+
+// node returns the node with the given id, nil if not found.
+func (w *wheelScheduler) node(id int) *Node {
+	return w.index[id]
+}
+
+// tickOf returns the tick of t (field), negative before the origin.
+func (w *wheelScheduler) tickOf(t time.Time) int64 {
+	return floorDiv(int64(t.Sub(w.origin)), int64(w.tick))
+}
+
+// place adds the node (field) in the slot of its next activation, or in due (field) if
+// its tick was moved past.
+func (w *wheelScheduler) place(node *Node) {
+	tick := w.tickOf(node.NextRun)
+	if tick < 0 || uint64(tick) < w.current {
+		push(&w.due, node)
+		return
+	}
+
+	// the first wheel whose turn holds both the tick and the current tick.
+	t := uint64(tick)
+	level := 0
+	for turn(t, level+1) != turn(w.current, level+1) {
+		level++
+	}
+
+	for len(w.wheels) <= level {
+		w.wheels = append(w.wheels, &wheel{})
+	}
+
+	slot := turn(t, level) & wheelMask
+	push(&w.wheels[level].slots[slot], node)
+	w.wheels[level].occupied |= 1 << slot
+}
+
+// nextSlot returns the start tick and the level of the first slot holding nodes, from
+// the current tick.
+//
+// reports false if the wheels are empty.
+func (w *wheelScheduler) nextSlot() (uint64, int, bool) {
+	var (
+		next  uint64
+		level int
+		found bool
+	)
+
+	for k, wh := range w.wheels {
+		// the current slot of the first wheel hasnt been moved past yet, the current slot
+		// of the others was spread in the wheels before them.
+		from := turn(w.current, k) & wheelMask
+		if k > 0 {
+			from++
+		}
+
+		for from < wheelSize {
+			mask := wh.occupied >> from << from
+			if mask == 0 {
+				break
+			}
+
+			slot := uint64(bits.TrailingZeros64(mask))
+			if wh.slots[slot] == nil {
+				wh.occupied &^= 1 << slot
+				continue
+			}
+
+			start := turn(w.current, k+1)<<(wheelBits*uint(k+1)) + slot<<(wheelBits*uint(k))
+
+			if !found || start < next {
+				next, level, found = start, k, true
+			}
+			break
+		}
+	}
+
+	return next, level, found
+}
+
+// advance moves the wheels past the tick: to (field), the nodes of the ticks moved past
+// are added to due (field).
+func (w *wheelScheduler) advance(to uint64) {
+	for {
+		tick, _, ok := w.nextSlot()
+		if !ok || tick > to {
+			break
+		}
+
+		w.current = tick
+
+		// spread the slots starting at the tick in the wheels before them, from the
+		// slowest wheel.
+		for k := len(w.wheels) - 1; k > 0; k-- {
+			if w.current&(1<<(wheelBits*uint(k))-1) != 0 {
+				continue
+			}
+
+			slot := turn(w.current, k) & wheelMask
+			nodes := w.wheels[k].slots[slot]
+			w.wheels[k].slots[slot] = nil
+			w.wheels[k].occupied &^= 1 << slot
+
+			for node := nodes; node != nil; {
+				next := node.next
+				w.place(node)
+				node = next
+			}
+		}
+
+		// the nodes of the tick are due.
+		slot := w.current & wheelMask
+		for node := w.wheels[0].slots[slot]; node != nil; {
+			next := node.next
+			push(&w.due, node)
+			node = next
+		}
+		w.wheels[0].slots[slot] = nil
+		w.wheels[0].occupied &^= 1 << slot
+
+		w.current++
+	}
+
+	if w.current <= to {
+		w.current = to + 1
+	}
+}
+
+// turn returns the turn of the wheel: level (field) at the tick: t (field), its slot in
+// the wheel before.
+func turn(t uint64, level int) uint64 {
+	if wheelBits*level >= 64 {
+		return 0
+	}
+
+	return t >> (wheelBits * uint(level))
+}
+
+// earliest returns the earliest next activation of the nodes in the list: list (field),
+// the zero time if its empty.
+func earliest(list *Node) (next time.Time) {
+	for node := list; node != nil; node = node.next {
+		if next.IsZero() || node.NextRun.Before(next) {
+			next = node.NextRun
+		}
+	}
+
+	return
+}
+
+// push adds the node (field) at the head of the list: list (field).
+func push(list **Node, node *Node) {
+	node.prev, node.next, node.list = nil, *list, list
+	if *list != nil {
+		(*list).prev = node
+	}
+	*list = node
+}
+
+// unlink removes the node (field) from its list.
+func unlink(node *Node) {
+	if node.list == nil {
+		return
+	}
+
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		*node.list = node.next
+	}
+	if node.next != nil {
+		node.next.prev = node.prev
+	}
+
+	node.prev, node.next, node.list = nil, nil, nil
+}
@@ -0,0 +1,177 @@
+package cronjob
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWheelScheduler(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	n1 := &Node{Id: 1, Schedule: In(now, 90*time.Minute)}
+	n2 := &Node{Id: 2, Schedule: Every(2 * time.Second)}
+	n3 := &Node{Id: 3, Schedule: In(now, 2500*time.Millisecond)}
+	n4 := &Node{Id: 4, Schedule: At(now.AddDate(30, 0, 0))}
+
+	w := NewWheelScheduler(time.Second)
+	if got, want := w.NextCycle(now), time.Duration(-1); got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	for _, node := range []*Node{n1, n2, n3, n4} {
+		w.AddNode(now, node)
+	}
+
+	if got, want := w.GetAll(), []*Node{n2, n3, n1, n4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	if got, want := w.NextCycle(now), 2*time.Second; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// nodes are due at their activation, not at the start of their tick.
+	nodes := w.RunNow(now.Add(2 * time.Second))
+	if got, want := nodes, []*Node{n2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	w.Clean(now.Add(2*time.Second), nodes)
+	if got, want := w.NextCycle(now.Add(2*time.Second)), 500*time.Millisecond; got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	nodes = w.RunNow(now.Add(2500 * time.Millisecond))
+	if got, want := nodes, []*Node{n3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+	w.Clean(now.Add(2500*time.Millisecond), nodes)
+
+	w.RemoveNode(n2.Id)
+	w.RemoveNode(42)
+	if got, want := w.GetAll(), []*Node{n1, n4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+
+	// far-future nodes run once the wheels reach them.
+	if got, want := w.RunNow(now.AddDate(30, 0, 0)), []*Node{n1, n4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestWheelSchedulerMatchesHeap(t *testing.T) {
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	now := start
+	r := rand.New(rand.NewSource(1))
+
+	// randomSchedule returns a schedule running in up to a few days, some far ahead.
+	randomSchedule := func() Schedule {
+		switch r.Intn(4) {
+		case 0:
+			return EveryFixed(time.Duration(1+r.Intn(7200)) * 100 * time.Millisecond)
+		case 1:
+			return Every(time.Duration(1+r.Intn(600)) * time.Second)
+		case 2:
+			return At(now.Add(time.Duration(r.Int63n(int64(72 * time.Hour)))))
+		default:
+			return At(now.Add(time.Duration(r.Int63n(int64(5 * 365 * day)))))
+		}
+	}
+
+	h, w := NewHeapScheduler(), NewWheelScheduler(time.Second)
+	add := func(id int) {
+		sched := randomSchedule()
+		h.AddNode(now, &Node{Id: id, Schedule: sched})
+		w.AddNode(now, &Node{Id: id, Schedule: sched})
+	}
+
+	id := 0
+	for ; id < 500; id++ {
+		add(id)
+	}
+
+	for i := 0; i < 5000; i++ {
+		// the wheels wake up no later than the heap.
+		want := h.NextCycle(now)
+		got := w.NextCycle(now)
+		if got < 0 || got > want {
+			t.Fatalf("cycle %v: got: %v want: at most %v", i, got, want)
+		}
+		now = now.Add(got)
+
+		gotNodes, wantNodes := w.RunNow(now), h.RunNow(now)
+		if ids(gotNodes) != ids(wantNodes) {
+			t.Fatalf("cycle %v at %v: got: %v want: %v", i, now, ids(gotNodes), ids(wantNodes))
+		}
+
+		w.Clean(now, gotNodes)
+		h.Clean(now, wantNodes)
+
+		// add and remove nodes now and then.
+		if i%20 == 0 {
+			add(id)
+			id++
+
+			remove := r.Intn(id)
+			h.RemoveNode(remove)
+			w.RemoveNode(remove)
+		}
+	}
+
+	if got, want := ids(w.GetAll()), ids(h.GetAll()); got != want {
+		t.Fatalf("got: %v want: %v", got, want)
+	}
+}
+
+func TestWheelSchedulerFarFuture(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	r := rand.New(rand.NewSource(1))
+
+	h, w := NewHeapScheduler(), NewWheelScheduler(time.Minute)
+	for id := 0; id < 1000; id++ {
+		sched := At(now.Add(time.Duration(r.Int63n(int64(20 * 365 * day)))))
+		h.AddNode(now, &Node{Id: id, Schedule: sched})
+		w.AddNode(now, &Node{Id: id, Schedule: sched})
+	}
+
+	// step with the wheels until every node ran exactly at its activation.
+	for cycles := 0; len(w.GetAll()) > 0; cycles++ {
+		if cycles > 100000 {
+			t.Fatalf("got: %v nodes left want: 0", len(w.GetAll()))
+		}
+		now = now.Add(w.NextCycle(now))
+
+		got, want := w.RunNow(now), h.RunNow(now)
+		if ids(got) != ids(want) {
+			t.Fatalf("at %v: got: %v want: %v", now, ids(got), ids(want))
+		}
+
+		w.Clean(now, got)
+		h.Clean(now, want)
+	}
+}
+
+func BenchmarkAddAt(b *testing.B) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	for _, sched := range schedulers {
+		b.Run(sched.name, func(b *testing.B) {
+			if sched.name == "linkedList" {
+				b.Skip("quadratic")
+			}
+
+			s := sched.newScheduler()
+			nodes := make([]Node, b.N)
+			for i := range nodes {
+				nodes[i] = Node{Id: i, Schedule: At(now.Add(time.Duration(i) * time.Second))}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range nodes {
+				s.AddNode(now, &nodes[i])
+			}
+		})
+	}
+}